@@ -7,6 +7,8 @@ import (
 	"image/draw"
 	"image/png"
 	"io"
+	"os"
+	"path/filepath"
 )
 
 //go:embed **/*
@@ -42,6 +44,19 @@ func LoadRawFont(name string) ([]byte, error) {
 	})
 }
 
+// LoadPrefab returns the raw definition of the named prefab. A copy under assets/prefabs
+// relative to the working directory takes precedence over the embedded one so prefabs can
+// be added or tuned without rebuilding the game.
+func LoadPrefab(name string) ([]byte, error) {
+	if bytes, err := os.ReadFile(filepath.Join("assets", "prefabs", name+".json")); err == nil {
+		return bytes, nil
+	}
+
+	return decodeAsset("prefabs", name, "json", func(r io.Reader) ([]byte, error) {
+		return io.ReadAll(r)
+	})
+}
+
 func decodeAsset[E any](assetType, name, assetExt string, reader func(r io.Reader) (E, error)) (val E, _ error) {
 	file, err := assets.Open(fmt.Sprintf("%s/%s.%s", assetType, name, assetExt))
 	if err != nil {
@@ -10,7 +10,7 @@ import (
 func main() {
 	opts := []engine.DelegateOption{
 		engine.WithInitialView(func(engineCtx *engine.Context) view.View {
-			return view.NewTransitionView(menu.NewMainMenu(engineCtx, gameplay.NewGameplay), engineCtx.ViewManager)
+			return view.NewTransitionView(menu.NewMainMenu(engineCtx, gameplay.NewGameplay, gameplay.LoadGameplay), engineCtx.ViewManager)
 		}),
 	}
 
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"sync"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/group"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/tag"
+)
+
+// Buffer records structural changes to the world so they can be applied at a well-defined
+// point instead of while a system is iterating over a collection. Commands are applied in
+// the order they were recorded. Commands recorded while the buffer is being flushed (e.g.
+// from an event listener reacting to an earlier command) are applied in the same flush.
+// Commands may be recorded concurrently by systems running in parallel.
+type Buffer struct {
+	mu            sync.Mutex
+	entityManager *entity.Manager
+	tagManager    *tag.Manager
+	groupManager  *group.Manager
+	commands      []func()
+	onError       func(err error)
+}
+
+type BufferOption func(*Buffer)
+
+// WithErrorHandler is called with errors from commands that fail when the buffer is
+// flushed, such as tagging an entity with a tag that is in use. By default such errors
+// panic, as they indicate conflicting structural changes within a single frame.
+func WithErrorHandler(onError func(err error)) BufferOption {
+	return func(b *Buffer) { b.onError = onError }
+}
+
+func NewBuffer(entityManager *entity.Manager, tagManager *tag.Manager, groupManager *group.Manager, opts ...BufferOption) *Buffer {
+	b := &Buffer{
+		entityManager: entityManager,
+		tagManager:    tagManager,
+		groupManager:  groupManager,
+		onError:       func(err error) { panic(err) },
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Defer records an arbitrary command.
+func (b *Buffer) Defer(f func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.commands = append(b.commands, f)
+}
+
+// Create records the creation of an entity. The setup function is invoked with the new
+// entity during the flush and may freely modify it.
+func (b *Buffer) Create(setup func(e entity.Entity)) {
+	b.Defer(func() {
+		e := b.entityManager.Create()
+		if setup != nil {
+			setup(e)
+		}
+	})
+}
+
+func (b *Buffer) Remove(e entity.Entity) {
+	b.Defer(func() { b.entityManager.Remove(e) })
+}
+
+func (b *Buffer) SetTag(e entity.Entity, t string) {
+	b.Defer(func() {
+		if err := b.tagManager.SetTag(e, t); err != nil {
+			b.onError(err)
+		}
+	})
+}
+
+func (b *Buffer) RemoveTag(e entity.Entity, t string) {
+	b.Defer(func() { b.tagManager.RemoveTag(e, t) })
+}
+
+func (b *Buffer) ClearTags(e entity.Entity) {
+	b.Defer(func() { b.tagManager.ClearTags(e) })
+}
+
+func (b *Buffer) AddGroup(e entity.Entity, g string) {
+	b.Defer(func() { b.groupManager.AddGroup(e, g) })
+}
+
+func (b *Buffer) RemoveGroup(e entity.Entity, g string) {
+	b.Defer(func() { b.groupManager.RemoveGroup(e, g) })
+}
+
+// Flush applies all recorded commands. It satisfies system.SyncPoint.
+func (b *Buffer) Flush() {
+	for {
+		b.mu.Lock()
+		commands := b.commands
+		b.commands = nil
+		b.mu.Unlock()
+
+		if len(commands) == 0 {
+			return
+		}
+
+		for _, command := range commands {
+			command()
+		}
+	}
+}
+
+func AddComponent[C component.Component[T], T component.ComponentType](b *Buffer, manager *component.TypedManager[C, T], e entity.Entity, c component.Component[T]) {
+	b.Defer(func() { manager.AddComponent(e, c) })
+}
+
+func RemoveComponent[C component.Component[T], T component.ComponentType](b *Buffer, manager *component.TypedManager[C, T], e entity.Entity) {
+	b.Defer(func() { manager.RemoveComponent(e) })
+}
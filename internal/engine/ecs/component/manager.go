@@ -6,14 +6,30 @@ import (
 )
 
 type Manager struct {
+	entityManager             *entity.Manager
 	entityChangedEventManager *entity.EntityChangedEventManager
-	componentsByEntityID      map[int64]map[ComponentType]Component[any]
+	store                     Store
+	hooks                     map[ComponentType]hooks
+	hookOrder                 []ComponentType // registration order, for deterministic removal
 }
 
-func NewManager(eventManager *event.Manager) *Manager {
+type ManagerOption func(*Manager)
+
+// WithStore replaces the default map-backed component storage.
+func WithStore(store Store) ManagerOption {
+	return func(m *Manager) { m.store = store }
+}
+
+func NewManager(entityManager *entity.Manager, eventManager *event.Manager, opts ...ManagerOption) *Manager {
 	m := &Manager{
+		entityManager:             entityManager,
 		entityChangedEventManager: entity.NewEntityChangedEventManager(eventManager),
-		componentsByEntityID:      map[int64]map[ComponentType]Component[any]{},
+		store:                     NewMapStore(),
+		hooks:                     map[ComponentType]hooks{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
 
 	entityRemovedEventManager := entity.NewEntityRemovedEventManager(eventManager)
@@ -21,51 +37,78 @@ func NewManager(eventManager *event.Manager) *Manager {
 	return m
 }
 
-func (m *Manager) HasComponent(e entity.Entity, componentType ComponentType) bool {
-	components, ok := m.componentsByEntityID[e.ID]
-	if !ok {
-		return false
-	}
+// HasComponent, GetComponent, AddComponent, and RemoveComponent treat stale entity
+// handles as having no components and ignore attempts to modify them.
 
-	_, ok = components[componentType]
-	return ok
+func (m *Manager) HasComponent(e entity.Entity, componentType ComponentType) bool {
+	return m.entityManager.IsAlive(e) && m.store.Has(e.ID, componentType)
 }
 
 func (m *Manager) GetComponent(e entity.Entity, componentType ComponentType) (component Component[any], _ bool) {
-	components, ok := m.componentsByEntityID[e.ID]
-	if !ok {
+	if !m.entityManager.IsAlive(e) {
 		return
 	}
 
-	component, ok = components[componentType]
-	return component, ok
+	return m.store.Get(e.ID, componentType)
 }
 
 func (m *Manager) AddComponent(e entity.Entity, component Component[any], componentType ComponentType) {
-	components, ok := m.componentsByEntityID[e.ID]
-	if !ok {
-		components = map[ComponentType]Component[any]{}
-		m.componentsByEntityID[e.ID] = components
+	if !m.entityManager.IsAlive(e) {
+		return
 	}
 
-	if _, ok := components[componentType]; !ok {
-		components[componentType] = component
+	if m.store.Add(e.ID, componentType, component) {
 		m.entityChangedEventManager.Dispatch(entity.EntityChangedEvent{Entity: e})
+
+		if hooks, ok := m.hooks[componentType]; ok {
+			hooks.added(e, component)
+		}
 	}
 }
 
 func (m *Manager) RemoveComponent(e entity.Entity, componentType ComponentType) {
-	components, ok := m.componentsByEntityID[e.ID]
+	if !m.entityManager.IsAlive(e) {
+		return
+	}
+
+	component, ok := m.store.Get(e.ID, componentType)
 	if !ok {
 		return
 	}
 
-	if _, ok := components[componentType]; ok {
-		delete(components, componentType)
+	if m.store.Remove(e.ID, componentType) {
 		m.entityChangedEventManager.Dispatch(entity.EntityChangedEvent{Entity: e})
+
+		if hooks, ok := m.hooks[componentType]; ok {
+			hooks.removed(e, component)
+		}
 	}
 }
 
 func (m *Manager) OnEntityRemoved(e entity.EntityRemovedEvent) {
-	delete(m.componentsByEntityID, e.Entity.ID)
+	// Collect observed components first so observers see the entity fully removed
+	type removal struct {
+		hooks     hooks
+		component Component[any]
+	}
+
+	var removals []removal
+	for _, componentType := range m.hookOrder {
+		if component, ok := m.store.Get(e.Entity.ID, componentType); ok {
+			removals = append(removals, removal{m.hooks[componentType], component})
+		}
+	}
+
+	m.store.RemoveEntity(e.Entity.ID)
+
+	for _, r := range removals {
+		r.hooks.removed(e.Entity, r.component)
+	}
+}
+
+func (m *Manager) registerHooks(componentType ComponentType, newHooks func() hooks) {
+	if _, ok := m.hooks[componentType]; !ok {
+		m.hooks[componentType] = newHooks()
+		m.hookOrder = append(m.hookOrder, componentType)
+	}
 }
@@ -0,0 +1,65 @@
+package component
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+type (
+	alphaType   struct{}
+	betaType    struct{}
+	gammaType   struct{}
+	deltaType   struct{}
+	epsilonType struct{}
+)
+
+type (
+	alpha   struct{}
+	beta    struct{}
+	gamma   struct{}
+	delta   struct{}
+	epsilon struct{}
+)
+
+func (*alpha) ComponentType() alphaType     { return alphaType{} }
+func (*beta) ComponentType() betaType       { return betaType{} }
+func (*gamma) ComponentType() gammaType     { return gammaType{} }
+func (*delta) ComponentType() deltaType     { return deltaType{} }
+func (*epsilon) ComponentType() epsilonType { return epsilonType{} }
+
+func TestEntityRemovalNotifiesInRegistrationOrder(t *testing.T) {
+	for _, store := range stores {
+		t.Run(store.name, func(t *testing.T) {
+			// Map iteration order varies between runs, so repeat to catch it
+			for i := 0; i < 20; i++ {
+				eventManager := event.NewManager()
+				entityManager := entity.NewManager(eventManager)
+				manager := NewManager(entityManager, eventManager, WithStore(store.new()))
+
+				var removed []string
+				e := entityManager.Create()
+				addObserved(manager, eventManager, e, "gamma", &gamma{}, &removed)
+				addObserved(manager, eventManager, e, "alpha", &alpha{}, &removed)
+				addObserved(manager, eventManager, e, "epsilon", &epsilon{}, &removed)
+				addObserved(manager, eventManager, e, "beta", &beta{}, &removed)
+				addObserved(manager, eventManager, e, "delta", &delta{}, &removed)
+
+				entityManager.Remove(e)
+
+				expected := []string{"gamma", "alpha", "epsilon", "beta", "delta"}
+				if !slices.Equal(removed, expected) {
+					t.Fatalf("unexpected removal order. want=%v have=%v", expected, removed)
+				}
+			}
+		})
+	}
+}
+
+func addObserved[C Component[T], T ComponentType](manager *Manager, eventManager *event.Manager, e entity.Entity, name string, component C, removed *[]string) {
+	typed := NewTypedManager[C](manager, eventManager)
+	typed.OnRemoved(func(entity.Entity, C) { *removed = append(*removed, name) })
+	typed.AddComponent(e, component)
+}
@@ -0,0 +1,72 @@
+package component
+
+type mapStore struct {
+	componentsByEntityID map[int64]map[ComponentType]Component[any]
+}
+
+func NewMapStore() Store {
+	return &mapStore{
+		componentsByEntityID: map[int64]map[ComponentType]Component[any]{},
+	}
+}
+
+func (s *mapStore) Has(id int64, componentType ComponentType) bool {
+	components, ok := s.componentsByEntityID[id]
+	if !ok {
+		return false
+	}
+
+	_, ok = components[componentType]
+	return ok
+}
+
+func (s *mapStore) Get(id int64, componentType ComponentType) (component Component[any], _ bool) {
+	components, ok := s.componentsByEntityID[id]
+	if !ok {
+		return
+	}
+
+	component, ok = components[componentType]
+	return component, ok
+}
+
+func (s *mapStore) Add(id int64, componentType ComponentType, component Component[any]) bool {
+	components, ok := s.componentsByEntityID[id]
+	if !ok {
+		components = map[ComponentType]Component[any]{}
+		s.componentsByEntityID[id] = components
+	}
+
+	if _, ok := components[componentType]; ok {
+		return false
+	}
+
+	components[componentType] = component
+	return true
+}
+
+func (s *mapStore) Remove(id int64, componentType ComponentType) bool {
+	components, ok := s.componentsByEntityID[id]
+	if !ok {
+		return false
+	}
+
+	if _, ok := components[componentType]; !ok {
+		return false
+	}
+
+	delete(components, componentType)
+	return true
+}
+
+func (s *mapStore) RemoveEntity(id int64) {
+	delete(s.componentsByEntityID, id)
+}
+
+func (s *mapStore) Each(componentType ComponentType, f func(id int64, component Component[any])) {
+	for id, components := range s.componentsByEntityID {
+		if component, ok := components[componentType]; ok {
+			f(id, component)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package component
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// Observer is notified with an entity and the component value concerned by a change.
+type Observer[C any] func(e entity.Entity, component C)
+
+type (
+	componentEvent[C Component[T], T ComponentType, K any] struct {
+		entity    entity.Entity
+		component C
+	}
+
+	// Distinguish the kinds of change of a single component type
+	componentAdded   struct{}
+	componentRemoved struct{}
+	componentChanged struct{}
+
+	componentEventType[T ComponentType, K any] struct{}
+)
+
+func (e componentEvent[C, T, K]) EventType() componentEventType[T, K] {
+	return componentEventType[T, K]{}
+}
+
+func (e componentEvent[C, T, K]) Notify(observer Observer[C]) {
+	observer(e.entity, e.component)
+}
+
+func newComponentEventManager[C Component[T], T ComponentType, K any](eventManager *event.Manager) *event.TypedManager[componentEvent[C, T, K], componentEventType[T, K], Observer[C]] {
+	return event.NewTypedManager[componentEvent[C, T, K], componentEventType[T, K], Observer[C]](eventManager)
+}
+
+// hooks let the untyped manager notify the observers of a component type. They are
+// registered once per component type by the first typed manager for that type.
+type hooks struct {
+	added   func(e entity.Entity, component Component[any])
+	removed func(e entity.Entity, component Component[any])
+}
+
+func newHooks[C Component[T], T ComponentType](eventManager *event.Manager) hooks {
+	added := newComponentEventManager[C, T, componentAdded](eventManager)
+	removed := newComponentEventManager[C, T, componentRemoved](eventManager)
+
+	return hooks{
+		added: func(e entity.Entity, component Component[any]) {
+			added.Dispatch(componentEvent[C, T, componentAdded]{entity: e, component: component.(C)})
+		},
+		removed: func(e entity.Entity, component Component[any]) {
+			removed.Dispatch(componentEvent[C, T, componentRemoved]{entity: e, component: component.(C)})
+		},
+	}
+}
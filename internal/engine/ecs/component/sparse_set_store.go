@@ -0,0 +1,192 @@
+package component
+
+// SparseSetStore keeps one densely packed column per component type. Each column maps
+// entity IDs to a slot in contiguous ID and value arrays, so lookups are a pair of slice
+// indexes and iteration over a single component type walks memory linearly.
+type SparseSetStore struct {
+	columns map[ComponentType]column
+}
+
+type column interface {
+	has(id int64) bool
+	get(id int64) (Component[any], bool)
+	add(id int64, component Component[any]) bool
+	remove(id int64) bool
+	each(f func(id int64, component Component[any]))
+}
+
+func NewSparseSetStore() *SparseSetStore {
+	return &SparseSetStore{
+		columns: map[ComponentType]column{},
+	}
+}
+
+func (s *SparseSetStore) Has(id int64, componentType ComponentType) bool {
+	column, ok := s.columns[componentType]
+	return ok && column.has(id)
+}
+
+func (s *SparseSetStore) Get(id int64, componentType ComponentType) (Component[any], bool) {
+	column, ok := s.columns[componentType]
+	if !ok {
+		return nil, false
+	}
+
+	return column.get(id)
+}
+
+func (s *SparseSetStore) Add(id int64, componentType ComponentType, component Component[any]) bool {
+	c, ok := s.columns[componentType]
+	if !ok {
+		c = newDenseColumn[Component[any]]()
+		s.columns[componentType] = c
+	}
+
+	return c.add(id, component)
+}
+
+func (s *SparseSetStore) Remove(id int64, componentType ComponentType) bool {
+	column, ok := s.columns[componentType]
+	return ok && column.remove(id)
+}
+
+func (s *SparseSetStore) RemoveEntity(id int64) {
+	for _, column := range s.columns {
+		column.remove(id)
+	}
+}
+
+func (s *SparseSetStore) Each(componentType ComponentType, f func(id int64, component Component[any])) {
+	if column, ok := s.columns[componentType]; ok {
+		column.each(f)
+	}
+}
+
+// registerColumn returns the column of concrete component values for the given type,
+// creating it if necessary. A nil result means the type already has an untyped column
+// (populated before any typed manager existed) and callers must use the generic path.
+func registerColumn[C Component[T], T ComponentType](s *SparseSetStore, componentType T) *denseColumn[C] {
+	if existing, ok := s.columns[componentType]; ok {
+		typed, _ := existing.(*denseColumn[C])
+		return typed
+	}
+
+	typed := newDenseColumn[C]()
+	s.columns[componentType] = typed
+	return typed
+}
+
+const pageSize = 1024
+
+type denseColumn[C any] struct {
+	pages      [][]int32 // entity ID -> dense index + 1; zero means absent
+	ids        []int64
+	values     []C
+	components []Component[any] // values as handed to the untyped manager API
+}
+
+func newDenseColumn[C any]() *denseColumn[C] {
+	return &denseColumn[C]{}
+}
+
+func (c *denseColumn[C]) index(id int64) (int, bool) {
+	page := id / pageSize
+	if id < 0 || page >= int64(len(c.pages)) || c.pages[page] == nil {
+		return 0, false
+	}
+
+	slot := c.pages[page][id%pageSize]
+	return int(slot) - 1, slot != 0
+}
+
+func (c *denseColumn[C]) setIndex(id int64, index int) {
+	page := id / pageSize
+	for int64(len(c.pages)) <= page {
+		c.pages = append(c.pages, nil)
+	}
+	if c.pages[page] == nil {
+		c.pages[page] = make([]int32, pageSize)
+	}
+
+	c.pages[page][id%pageSize] = int32(index + 1)
+}
+
+func (c *denseColumn[C]) has(id int64) bool {
+	_, ok := c.index(id)
+	return ok
+}
+
+func (c *denseColumn[C]) getTyped(id int64) (value C, _ bool) {
+	index, ok := c.index(id)
+	if !ok {
+		return
+	}
+
+	return c.values[index], true
+}
+
+func (c *denseColumn[C]) get(id int64) (Component[any], bool) {
+	index, ok := c.index(id)
+	if !ok {
+		return nil, false
+	}
+
+	return c.components[index], true
+}
+
+func (c *denseColumn[C]) add(id int64, component Component[any]) bool {
+	if id < 0 {
+		panic("negative entity id")
+	}
+	if c.has(id) {
+		return false
+	}
+
+	value, ok := component.(C)
+	if !ok {
+		panic("malformed component store")
+	}
+
+	c.setIndex(id, len(c.values))
+	c.ids = append(c.ids, id)
+	c.values = append(c.values, value)
+	c.components = append(c.components, component)
+	return true
+}
+
+func (c *denseColumn[C]) remove(id int64) bool {
+	index, ok := c.index(id)
+	if !ok {
+		return false
+	}
+
+	// Swap the last element into the vacated slot to keep the arrays dense
+	last := len(c.values) - 1
+	if index != last {
+		c.ids[index] = c.ids[last]
+		c.values[index] = c.values[last]
+		c.components[index] = c.components[last]
+		c.setIndex(c.ids[index], index)
+	}
+
+	var zero C
+	c.values[last] = zero
+	c.components[last] = nil
+	c.ids = c.ids[:last]
+	c.values = c.values[:last]
+	c.components = c.components[:last]
+	c.pages[id/pageSize][id%pageSize] = 0
+	return true
+}
+
+func (c *denseColumn[C]) each(f func(id int64, component Component[any])) {
+	for i, component := range c.components {
+		f(c.ids[i], component)
+	}
+}
+
+func (c *denseColumn[C]) eachTyped(f func(id int64, component C)) {
+	for i, value := range c.values {
+		f(c.ids[i], value)
+	}
+}
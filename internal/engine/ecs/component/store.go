@@ -0,0 +1,12 @@
+package component
+
+// Store holds component values keyed by entity ID and component type. The manager
+// owns event dispatch; stores only track membership and values.
+type Store interface {
+	Has(id int64, componentType ComponentType) bool
+	Get(id int64, componentType ComponentType) (Component[any], bool)
+	Add(id int64, componentType ComponentType, component Component[any]) bool
+	Remove(id int64, componentType ComponentType) bool
+	RemoveEntity(id int64)
+	Each(componentType ComponentType, f func(id int64, component Component[any]))
+}
@@ -0,0 +1,209 @@
+package component
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type testComponentType struct{}
+
+type testComponent struct {
+	id int64
+}
+
+func (c *testComponent) ComponentType() any { return testComponentType{} }
+
+type otherComponentType struct{}
+
+type otherComponent struct{}
+
+func (c *otherComponent) ComponentType() any { return otherComponentType{} }
+
+var stores = []struct {
+	name string
+	new  func() Store
+}{
+	{"map", NewMapStore},
+	{"sparse", func() Store { return NewSparseSetStore() }},
+}
+
+func TestStores(t *testing.T) {
+	for _, store := range stores {
+		t.Run(store.name, func(t *testing.T) {
+			s := store.new()
+
+			if !s.Add(1, testComponentType{}, &testComponent{1}) {
+				t.Fatalf("expected first add to succeed")
+			}
+			if s.Add(1, testComponentType{}, &testComponent{1}) {
+				t.Fatalf("expected duplicate add to fail")
+			}
+			s.Add(1, otherComponentType{}, &otherComponent{})
+			s.Add(2, testComponentType{}, &testComponent{2})
+
+			if !s.Has(1, otherComponentType{}) || s.Has(2, otherComponentType{}) {
+				t.Fatalf("unexpected membership of other component")
+			}
+			if component, ok := s.Get(2, testComponentType{}); !ok || component.(*testComponent).id != 2 {
+				t.Fatalf("unexpected component %v (ok=%v)", component, ok)
+			}
+
+			if !s.Remove(1, testComponentType{}) || s.Remove(1, testComponentType{}) {
+				t.Fatalf("expected exactly one removal to succeed")
+			}
+			if !s.Has(1, otherComponentType{}) {
+				t.Fatalf("expected removal to leave other components")
+			}
+
+			s.RemoveEntity(1)
+			if s.Has(1, otherComponentType{}) {
+				t.Fatalf("expected entity removal to remove all components")
+			}
+			if ids := eachIDs(s); len(ids) != 1 || ids[0] != 2 {
+				t.Fatalf("unexpected ids %v", ids)
+			}
+		})
+	}
+}
+
+func TestSparseSetStoreSwapRemove(t *testing.T) {
+	const n = 3 * pageSize
+
+	s := NewSparseSetStore()
+	present := map[int64]bool{}
+	for id := int64(0); id < n; id++ {
+		s.Add(id, testComponentType{}, &testComponent{id})
+		present[id] = true
+	}
+
+	// Remove from the front, middle and back, including the last dense element
+	r := rand.New(rand.NewSource(1))
+	for _, id := range append([]int{0, n - 1, n / 2}, r.Perm(n)[:n/2]...) {
+		if s.Remove(int64(id), testComponentType{}) != present[int64(id)] {
+			t.Fatalf("unexpected removal result for %d", id)
+		}
+		delete(present, int64(id))
+		assertColumnConsistent(t, s.columns[testComponentType{}].(*denseColumn[Component[any]]), present)
+	}
+
+	// Re-adding removed entities must reuse the sparse slots correctly
+	for id := int64(0); id < n; id += 7 {
+		if s.Add(id, testComponentType{}, &testComponent{id}) == present[id] {
+			t.Fatalf("unexpected add result for %d", id)
+		}
+		present[id] = true
+	}
+	assertColumnConsistent(t, s.columns[testComponentType{}].(*denseColumn[Component[any]]), present)
+}
+
+func assertColumnConsistent(t *testing.T, c *denseColumn[Component[any]], present map[int64]bool) {
+	t.Helper()
+
+	if len(c.ids) != len(present) || len(c.values) != len(present) || len(c.components) != len(present) {
+		t.Fatalf("expected %d dense entries, have %d ids, %d values", len(present), len(c.ids), len(c.values))
+	}
+
+	for i, id := range c.ids {
+		if index, ok := c.index(id); !ok || index != i {
+			t.Fatalf("sparse index of %d is %d (ok=%v), expected %d", id, index, ok, i)
+		}
+		if c.components[i].(*testComponent).id != id {
+			t.Fatalf("dense slot %d holds the component of %d, expected %d", i, c.components[i].(*testComponent).id, id)
+		}
+		if !present[id] {
+			t.Fatalf("removed entity %d is still dense", id)
+		}
+	}
+
+	for id := range present {
+		if !c.has(id) {
+			t.Fatalf("entity %d is missing", id)
+		}
+	}
+}
+
+func eachIDs(s Store) (ids []int64) {
+	s.Each(testComponentType{}, func(id int64, _ Component[any]) { ids = append(ids, id) })
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+var benchmarkSizes = []int{10_000, 100_000}
+
+func populate(s Store, n int) {
+	for id := int64(0); id < int64(n); id++ {
+		s.Add(id, testComponentType{}, &testComponent{id})
+		if id%2 == 0 {
+			s.Add(id, otherComponentType{}, &otherComponent{})
+		}
+	}
+}
+
+func benchmarkStores(b *testing.B, f func(b *testing.B, s Store, n int)) {
+	for _, store := range stores {
+		for _, n := range benchmarkSizes {
+			b.Run(fmt.Sprintf("%s/%d", store.name, n), func(b *testing.B) {
+				s := store.new()
+				populate(s, n)
+				b.ResetTimer()
+				f(b, s, n)
+			})
+		}
+	}
+}
+
+func BenchmarkStoreGet(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s Store, n int) {
+		for i := 0; i < b.N; i++ {
+			s.Get(int64(i%n), testComponentType{})
+		}
+	})
+}
+
+func BenchmarkStoreHas(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s Store, n int) {
+		for i := 0; i < b.N; i++ {
+			s.Has(int64(i%n), otherComponentType{})
+		}
+	})
+}
+
+func BenchmarkStoreEach(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s Store, n int) {
+		for i := 0; i < b.N; i++ {
+			s.Each(testComponentType{}, func(int64, Component[any]) {})
+		}
+	})
+}
+
+func BenchmarkStoreAdd(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s Store, n int) {
+		// Add entities beyond those already populated, then reset to keep the store size steady
+		for i := 0; i < b.N; i++ {
+			id := int64(n + i%n)
+			s.Add(id, testComponentType{}, &testComponent{id})
+			if i%n == n-1 {
+				b.StopTimer()
+				for id := int64(n); id < int64(2*n); id++ {
+					s.Remove(id, testComponentType{})
+				}
+				b.StartTimer()
+			}
+		}
+	})
+}
+
+func BenchmarkStoreRemove(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s Store, n int) {
+		// Remove each entity and put it back untimed so every iteration removes a component
+		for i := 0; i < b.N; i++ {
+			id := int64(i % n)
+			s.Remove(id, testComponentType{})
+			b.StopTimer()
+			s.Add(id, testComponentType{}, &testComponent{id})
+			b.StartTimer()
+		}
+	})
+}
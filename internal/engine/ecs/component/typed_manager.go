@@ -6,21 +6,51 @@ import (
 )
 
 type TypedManager[C Component[T], T ComponentType] struct {
-	manager *Manager
+	manager      *Manager
+	eventManager *event.Manager
+	column       *denseColumn[C] // nil unless backed by a sparse set store
+	added        *event.TypedManager[componentEvent[C, T, componentAdded], componentEventType[T, componentAdded], Observer[C]]
+	removed      *event.TypedManager[componentEvent[C, T, componentRemoved], componentEventType[T, componentRemoved], Observer[C]]
+	changed      *event.TypedManager[componentEvent[C, T, componentChanged], componentEventType[T, componentChanged], Observer[C]]
 }
 
 func NewTypedManager[C Component[T], T ComponentType](manager *Manager, eventManager *event.Manager) *TypedManager[C, T] {
-	return &TypedManager[C, T]{
-		manager: manager,
+	var componentType T // Infer component type value from type param
+
+	m := &TypedManager[C, T]{
+		manager:      manager,
+		eventManager: eventManager,
+		added:        newComponentEventManager[C, T, componentAdded](eventManager),
+		removed:      newComponentEventManager[C, T, componentRemoved](eventManager),
+		changed:      newComponentEventManager[C, T, componentChanged](eventManager),
+	}
+
+	if store, ok := manager.store.(*SparseSetStore); ok {
+		m.column = registerColumn[C](store, componentType)
 	}
+
+	manager.registerHooks(componentType, func() hooks { return newHooks[C](eventManager) })
+	return m
 }
 
 func (m *TypedManager[C, T]) HasComponent(e entity.Entity) bool {
+	if m.column != nil {
+		return m.manager.entityManager.IsAlive(e) && m.column.has(e.ID)
+	}
+
 	var componentType T // Infer component type value from type param
 	return m.manager.HasComponent(e, componentType)
 }
 
 func (m *TypedManager[C, T]) GetComponent(e entity.Entity) (component C, _ bool) {
+	if m.column != nil {
+		if !m.manager.entityManager.IsAlive(e) {
+			return
+		}
+
+		return m.column.getTyped(e.ID)
+	}
+
 	var componentType T // Infer component type value from type param
 	rawComponent, ok := m.manager.GetComponent(e, componentType)
 	if !ok {
@@ -44,3 +74,53 @@ func (m *TypedManager[C, T]) RemoveComponent(e entity.Entity) {
 	var componentType T // Infer component type value from type param
 	m.manager.RemoveComponent(e, componentType)
 }
+
+// Each calls f for every entity with a component of this type. With a sparse set store
+// this walks the packed column directly. Components must not be added or removed from
+// within f.
+func (m *TypedManager[C, T]) Each(f func(e entity.Entity, component C)) {
+	if m.column != nil {
+		m.column.eachTyped(func(id int64, component C) { f(m.manager.entityManager.Get(id), component) })
+		return
+	}
+
+	var componentType T // Infer component type value from type param
+	m.manager.store.Each(componentType, func(id int64, rawComponent Component[any]) {
+		component, ok := rawComponent.(C)
+		if !ok {
+			panic("malformed component manager")
+		}
+
+		f(m.manager.entityManager.Get(id), component)
+	})
+}
+
+// Observers registered through any typed manager of a component type are notified of
+// changes made through every manager of that type. Observers of removals receive the
+// removed value, including when the whole entity is removed.
+
+func (m *TypedManager[C, T]) OnAdded(observer Observer[C], opts ...event.ListenerOption) event.ListenerHandle {
+	return m.added.AddListener(observer, opts...)
+}
+
+func (m *TypedManager[C, T]) OnRemoved(observer Observer[C], opts ...event.ListenerOption) event.ListenerHandle {
+	return m.removed.AddListener(observer, opts...)
+}
+
+// OnChanged observes components flagged with MarkChanged. Mutating a component in place
+// does not notify observers by itself.
+func (m *TypedManager[C, T]) OnChanged(observer Observer[C], opts ...event.ListenerOption) event.ListenerHandle {
+	return m.changed.AddListener(observer, opts...)
+}
+
+func (m *TypedManager[C, T]) RemoveObserver(handle event.ListenerHandle) {
+	m.eventManager.RemoveListener(handle)
+}
+
+// MarkChanged notifies OnChanged observers that the component of the given entity has
+// been modified. It is a no-op if the entity has no such component.
+func (m *TypedManager[C, T]) MarkChanged(e entity.Entity) {
+	if component, ok := m.GetComponent(e); ok {
+		m.changed.Dispatch(componentEvent[C, T, componentChanged]{entity: e, component: component})
+	}
+}
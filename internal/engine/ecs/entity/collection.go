@@ -1,16 +1,43 @@
 package entity
 
-import "github.com/efritz/lunar-fever/internal/engine/event"
+import (
+	"cmp"
+	"slices"
+	"sync"
 
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// Collection tracks the entities accepted by a matcher. Entities are always returned in
+// ascending ID order so that systems iterating a collection behave reproducibly. A
+// collection may be read by systems running in parallel.
 type Collection struct {
-	matcher  Matcher
-	entities map[int64]Entity
+	mu          sync.Mutex
+	matcher     Matcher
+	members     map[int64]Entity
+	entities    []Entity
+	dirty       bool
+	shared      bool
+	incremental bool
 }
 
-func NewCollection(matcher Matcher, eventManager *event.Manager) *Collection {
+type CollectionOption func(*Collection)
+
+// WithIncrementalIndex keeps the ordered entity slice up to date on every change instead
+// of rebuilding it from scratch on the next read. Prefer this for large collections with
+// a low rate of churn.
+func WithIncrementalIndex() CollectionOption {
+	return func(c *Collection) { c.incremental = true }
+}
+
+func NewCollection(matcher Matcher, eventManager *event.Manager, opts ...CollectionOption) *Collection {
 	c := &Collection{
-		matcher:  matcher,
-		entities: map[int64]Entity{},
+		matcher: matcher,
+		members: map[int64]Entity{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
 	entityChangedEventManager := NewEntityChangedEventManager(eventManager)
@@ -20,23 +47,107 @@ func NewCollection(matcher Matcher, eventManager *event.Manager) *Collection {
 	return c
 }
 
+// Entities returns the members of the collection ordered by ID. The returned slice is
+// shared between calls and must not be modified. It is not affected by changes to the
+// collection made while the caller is iterating over it.
 func (c *Collection) Entities() []Entity {
-	entities := make([]Entity, 0, len(c.entities))
-	for _, e := range c.entities {
-		entities = append(entities, e)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.dirty {
+		entities := make([]Entity, 0, len(c.members))
+		for _, e := range c.members {
+			entities = append(entities, e)
+		}
+		slices.SortFunc(entities, compareEntities)
+
+		c.entities = entities
+		c.dirty = false
 	}
 
-	return entities
+	c.shared = true
+	return c.entities
+}
+
+func (c *Collection) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.members)
+}
+
+func (c *Collection) Contains(e Entity) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	member, ok := c.members[e.ID]
+	return ok && member == e
 }
 
 func (c *Collection) OnEntityChanged(e EntityChangedEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.matcher.Matches(e.Entity) {
-		c.entities[e.Entity.ID] = e.Entity
+		c.add(e.Entity)
 	} else {
-		delete(c.entities, e.Entity.ID)
+		c.remove(e.Entity)
 	}
 }
 
 func (c *Collection) OnEntityRemoved(e EntityRemovedEvent) {
-	delete(c.entities, e.Entity.ID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(e.Entity)
+}
+
+func (c *Collection) add(e Entity) {
+	if _, ok := c.members[e.ID]; ok {
+		return
+	}
+	c.members[e.ID] = e
+
+	if !c.incremental {
+		c.dirty = true
+		return
+	}
+
+	c.unshare()
+	index, _ := slices.BinarySearchFunc(c.entities, e, compareEntities)
+	c.entities = slices.Insert(c.entities, index, e)
+}
+
+func (c *Collection) remove(e Entity) {
+	if _, ok := c.members[e.ID]; !ok {
+		return
+	}
+	delete(c.members, e.ID)
+
+	if !c.incremental {
+		c.dirty = true
+		return
+	}
+
+	c.unshare()
+	if index, ok := slices.BinarySearchFunc(c.entities, e, compareEntities); ok {
+		c.entities = slices.Delete(c.entities, index, index+1)
+	}
+}
+
+// unshare copies the ordered slice before an in-place update if it may still be in use
+// by a caller of Entities.
+func (c *Collection) unshare() {
+	if c.shared {
+		c.entities = slices.Clone(c.entities)
+		c.shared = false
+	}
+}
+
+func compareEntities(a, b Entity) int {
+	if c := cmp.Compare(a.ID, b.ID); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Generation, b.Generation)
 }
@@ -1,5 +1,9 @@
 package entity
 
+// Entity is a handle to an entity slot. IDs are recycled once an entity is removed;
+// the generation distinguishes the current occupant of a slot from stale handles to
+// earlier ones.
 type Entity struct {
-	ID int64
+	ID         int64
+	Generation uint32
 }
@@ -1,18 +1,22 @@
 package group
 
 import (
+	"sort"
+
 	"github.com/efritz/lunar-fever/internal/common/datastructures"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/event"
 )
 
 type Manager struct {
+	entityManager             *entity.Manager
 	entityChangedEventManager *entity.EntityChangedEventManager
 	groupsByEntityID          map[int64]datastructures.Set[string]
 }
 
-func NewManager(eventManager *event.Manager) *Manager {
+func NewManager(entityManager *entity.Manager, eventManager *event.Manager) *Manager {
 	m := &Manager{
+		entityManager:             entityManager,
 		entityChangedEventManager: entity.NewEntityChangedEventManager(eventManager),
 		groupsByEntityID:          map[int64]datastructures.Set[string]{},
 	}
@@ -22,12 +26,38 @@ func NewManager(eventManager *event.Manager) *Manager {
 	return m
 }
 
+// HasGroup, AddGroup, and RemoveGroup treat stale entity handles as belonging to no
+// group and ignore attempts to modify them.
+
 func (m *Manager) HasGroup(e entity.Entity, group string) bool {
+	if !m.entityManager.IsAlive(e) {
+		return false
+	}
+
 	_, ok := m.groupsByEntityID[e.ID][group]
 	return ok
 }
 
+// Groups returns the groups of the given entity in lexical order.
+func (m *Manager) Groups(e entity.Entity) []string {
+	if !m.entityManager.IsAlive(e) {
+		return nil
+	}
+
+	groups := make([]string, 0, len(m.groupsByEntityID[e.ID]))
+	for group := range m.groupsByEntityID[e.ID] {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
+
 func (m *Manager) AddGroup(e entity.Entity, group string) {
+	if !m.entityManager.IsAlive(e) {
+		return
+	}
+
 	groups, ok := m.groupsByEntityID[e.ID]
 	if !ok {
 		groups = datastructures.Set[string]{}
@@ -41,6 +71,10 @@ func (m *Manager) AddGroup(e entity.Entity, group string) {
 }
 
 func (m *Manager) RemoveGroup(e entity.Entity, group string) {
+	if !m.entityManager.IsAlive(e) {
+		return
+	}
+
 	groups, ok := m.groupsByEntityID[e.ID]
 	if !ok {
 		return
@@ -1,31 +1,87 @@
 package entity
 
-import (
-	"sync/atomic"
-
-	"github.com/efritz/lunar-fever/internal/engine/event"
-)
+import "github.com/efritz/lunar-fever/internal/engine/event"
 
 type Manager struct {
-	id                        int64
+	generations               []uint32 // indexed by ID; slot zero is never handed out
+	alive                     []bool
+	free                      []int64
 	entityCreatedEventManager *EntityCreatedEventManager
 	entityRemovedEventManager *EntityRemovedEventManager
 }
 
 func NewManager(eventManager *event.Manager) *Manager {
 	return &Manager{
+		generations:               []uint32{0},
+		alive:                     []bool{false},
 		entityCreatedEventManager: NewEntityCreatedEventManager(eventManager),
 		entityRemovedEventManager: NewEntityRemovedEventManager(eventManager),
 	}
 }
 
 func (m *Manager) Create() Entity {
-	id := atomic.AddInt64(&m.id, 1)
-	entity := Entity{ID: id}
+	entity := m.allocate()
 	m.entityCreatedEventManager.Dispatch(EntityCreatedEvent{Entity: entity})
 	return entity
 }
 
+// Remove dispatches an EntityRemovedEvent for a live entity and then frees its ID for
+// reuse. Removing a stale handle is a no-op.
 func (m *Manager) Remove(entity Entity) {
+	if !m.IsAlive(entity) {
+		return
+	}
+
 	m.entityRemovedEventManager.Dispatch(EntityRemovedEvent{Entity: entity})
+	m.release(entity)
+}
+
+func (m *Manager) IsAlive(entity Entity) bool {
+	if entity.ID <= 0 || entity.ID >= int64(len(m.generations)) {
+		return false
+	}
+
+	return m.alive[entity.ID] && m.generations[entity.ID] == entity.Generation
+}
+
+// Get returns the handle of the live entity occupying the given ID.
+func (m *Manager) Get(id int64) Entity {
+	return Entity{ID: id, Generation: m.generations[id]}
+}
+
+// Entities returns all live entities in ascending ID order.
+func (m *Manager) Entities() []Entity {
+	var entities []Entity
+	for id, alive := range m.alive {
+		if alive {
+			entities = append(entities, Entity{ID: int64(id), Generation: m.generations[id]})
+		}
+	}
+
+	return entities
+}
+
+func (m *Manager) allocate() Entity {
+	// Reuse the least recently freed ID so stale handles stay invalid for as long as possible
+	if len(m.free) > 0 {
+		id := m.free[0]
+		m.free = m.free[1:]
+		m.alive[id] = true
+		return Entity{ID: id, Generation: m.generations[id]}
+	}
+
+	id := int64(len(m.generations))
+	m.generations = append(m.generations, 0)
+	m.alive = append(m.alive, true)
+	return Entity{ID: id}
+}
+
+func (m *Manager) release(entity Entity) {
+	if !m.IsAlive(entity) {
+		return
+	}
+
+	m.alive[entity.ID] = false
+	m.generations[entity.ID]++
+	m.free = append(m.free, entity.ID)
 }
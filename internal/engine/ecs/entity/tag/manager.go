@@ -1,22 +1,40 @@
 package tag
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/efritz/lunar-fever/internal/common/datastructures"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/event"
 )
 
+// Manager assigns tags to entities. A tag names exactly one live entity at a time (e.g.
+// "player"), so it can be used to look that entity up directly with Find. An entity may
+// carry any number of tags. Use groups (see package group) for labels shared by many
+// entities, such as "npc" or "door".
 type Manager struct {
+	entityManager             *entity.Manager
 	entityChangedEventManager *entity.EntityChangedEventManager
-	tagByEntityID             map[int64]string
-	tags                      datastructures.Set[string]
+	tagsByEntityID            map[int64]datastructures.Set[string]
+	entityByTag               map[string]entity.Entity
 }
 
-func NewManager(eventManager *event.Manager) *Manager {
+var (
+	// ErrTagInUse is returned when assigning a tag that already names another entity.
+	ErrTagInUse = errors.New("tag already in use")
+
+	// ErrStaleEntity is returned when assigning a tag to an entity that has been removed.
+	ErrStaleEntity = errors.New("entity is not alive")
+)
+
+func NewManager(entityManager *entity.Manager, eventManager *event.Manager) *Manager {
 	m := &Manager{
+		entityManager:             entityManager,
 		entityChangedEventManager: entity.NewEntityChangedEventManager(eventManager),
-		tagByEntityID:             map[int64]string{},
-		tags:                      datastructures.Set[string]{},
+		tagsByEntityID:            map[int64]datastructures.Set[string]{},
+		entityByTag:               map[string]entity.Entity{},
 	}
 
 	entityRemovedEventManager := entity.NewEntityRemovedEventManager(eventManager)
@@ -24,37 +42,117 @@ func NewManager(eventManager *event.Manager) *Manager {
 	return m
 }
 
+// HasTag, Tags, RemoveTag, and ClearTags treat stale entity handles as untagged and ignore
+// attempts to modify them. SetTag reports them with ErrStaleEntity, as the caller would
+// otherwise expect the tag to name the entity.
+
 func (m *Manager) HasTag(e entity.Entity, tag string) bool {
-	return m.tagByEntityID[e.ID] == tag
+	if !m.entityManager.IsAlive(e) {
+		return false
+	}
+
+	_, ok := m.tagsByEntityID[e.ID][tag]
+	return ok
 }
 
-func (m *Manager) SetTag(e entity.Entity, tag string) {
-	if _, ok := m.tags[tag]; ok {
-		panic("tag already set")
+// Tags returns the tags of the given entity in lexical order.
+func (m *Manager) Tags(e entity.Entity) []string {
+	if !m.entityManager.IsAlive(e) {
+		return nil
+	}
+
+	tags := make([]string, 0, len(m.tagsByEntityID[e.ID]))
+	for tag := range m.tagsByEntityID[e.ID] {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// Find returns the entity currently carrying the given tag.
+func (m *Manager) Find(tag string) (entity.Entity, bool) {
+	e, ok := m.entityByTag[tag]
+	return e, ok
+}
+
+// SetTag adds a tag to the given entity. Setting a tag the entity already carries is a
+// no-op. If the tag names a different entity, ErrTagInUse is returned and nothing changes;
+// remove the tag from its current owner first to move it.
+func (m *Manager) SetTag(e entity.Entity, tag string) error {
+	if !m.entityManager.IsAlive(e) {
+		return fmt.Errorf("tagging entity %d as %q: %w", e.ID, tag, ErrStaleEntity)
+	}
+
+	if owner, ok := m.entityByTag[tag]; ok {
+		if owner == e {
+			return nil
+		}
+
+		return fmt.Errorf("tagging entity %d as %q: %w (entity %d)", e.ID, tag, ErrTagInUse, owner.ID)
 	}
 
-	m.tags[tag] = struct{}{}
-	m.tagByEntityID[e.ID] = tag
+	tags, ok := m.tagsByEntityID[e.ID]
+	if !ok {
+		tags = datastructures.Set[string]{}
+		m.tagsByEntityID[e.ID] = tags
+	}
+
+	tags[tag] = struct{}{}
+	m.entityByTag[tag] = e
 	m.entityChangedEventManager.Dispatch(entity.EntityChangedEvent{Entity: e})
+	return nil
 }
 
-func (m *Manager) RemoveTag(e entity.Entity) {
-	if m.removeTag(e) {
+// RemoveTag removes a single tag from the given entity, freeing it for use by another.
+func (m *Manager) RemoveTag(e entity.Entity, tag string) {
+	if !m.entityManager.IsAlive(e) {
+		return
+	}
+
+	if m.removeTag(e, tag) {
+		m.entityChangedEventManager.Dispatch(entity.EntityChangedEvent{Entity: e})
+	}
+}
+
+// ClearTags removes all tags from the given entity.
+func (m *Manager) ClearTags(e entity.Entity) {
+	if !m.entityManager.IsAlive(e) {
+		return
+	}
+
+	if m.clearTags(e) {
 		m.entityChangedEventManager.Dispatch(entity.EntityChangedEvent{Entity: e})
 	}
 }
 
 func (m *Manager) OnEntityRemoved(e entity.EntityRemovedEvent) {
-	_ = m.removeTag(e.Entity)
+	_ = m.clearTags(e.Entity)
 }
 
-func (m *Manager) removeTag(e entity.Entity) bool {
-	tag, ok := m.tagByEntityID[e.ID]
+func (m *Manager) removeTag(e entity.Entity, tag string) bool {
+	tags := m.tagsByEntityID[e.ID]
+	if _, ok := tags[tag]; !ok {
+		return false
+	}
+
+	delete(tags, tag)
+	if len(tags) == 0 {
+		delete(m.tagsByEntityID, e.ID)
+	}
+	delete(m.entityByTag, tag)
+	return true
+}
+
+func (m *Manager) clearTags(e entity.Entity) bool {
+	tags, ok := m.tagsByEntityID[e.ID]
 	if !ok {
 		return false
 	}
 
-	delete(m.tagByEntityID, e.ID)
-	delete(m.tags, tag)
+	for tag := range tags {
+		delete(m.entityByTag, tag)
+	}
+	delete(m.tagsByEntityID, e.ID)
 	return true
 }
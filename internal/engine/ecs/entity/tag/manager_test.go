@@ -0,0 +1,167 @@
+package tag
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+func newTestManager() (*Manager, *entity.Manager) {
+	eventManager := event.NewManager()
+	entityManager := entity.NewManager(eventManager)
+	return NewManager(entityManager, eventManager), entityManager
+}
+
+func TestSetTag(t *testing.T) {
+	m, entities := newTestManager()
+	e := entities.Create()
+
+	if err := m.SetTag(e, "player"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := m.SetTag(e, "player"); err != nil {
+		t.Fatalf("expected setting a tag twice to be a no-op, got %s", err)
+	}
+	if err := m.SetTag(e, "hero"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if found, ok := m.Find("player"); !ok || found != e {
+		t.Fatalf("expected to find %v, got %v (ok=%v)", e, found, ok)
+	}
+	if !m.HasTag(e, "hero") || m.HasTag(e, "villain") {
+		t.Fatalf("unexpected tags %v", m.Tags(e))
+	}
+	if tags := m.Tags(e); !slices.Equal(tags, []string{"hero", "player"}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	if _, ok := m.Find("villain"); ok {
+		t.Fatalf("expected unused tag to be missing")
+	}
+}
+
+func TestSetTagInUse(t *testing.T) {
+	m, entities := newTestManager()
+	owner, other := entities.Create(), entities.Create()
+
+	_ = m.SetTag(owner, "player")
+	if err := m.SetTag(other, "player"); !errors.Is(err, ErrTagInUse) {
+		t.Fatalf("expected ErrTagInUse, got %v", err)
+	}
+
+	if found, _ := m.Find("player"); found != owner {
+		t.Fatalf("expected tag to remain with %v, found %v", owner, found)
+	}
+	if m.HasTag(other, "player") {
+		t.Fatalf("expected failed tagging to leave the entity untagged")
+	}
+}
+
+func TestSetTagStaleEntity(t *testing.T) {
+	m, entities := newTestManager()
+	e := entities.Create()
+	entities.Remove(e)
+
+	if err := m.SetTag(e, "player"); !errors.Is(err, ErrStaleEntity) {
+		t.Fatalf("expected ErrStaleEntity, got %v", err)
+	}
+	if _, ok := m.Find("player"); ok {
+		t.Fatalf("expected stale entity to be left untagged")
+	}
+}
+
+func TestRemoveTag(t *testing.T) {
+	m, entities := newTestManager()
+	e, other := entities.Create(), entities.Create()
+
+	_ = m.SetTag(e, "player")
+	_ = m.SetTag(e, "hero")
+	m.RemoveTag(e, "player")
+	m.RemoveTag(e, "player") // no-op
+
+	if _, ok := m.Find("player"); ok {
+		t.Fatalf("expected removed tag to be missing")
+	}
+	if !m.HasTag(e, "hero") {
+		t.Fatalf("expected other tags to remain")
+	}
+
+	// The tag is free to move to another entity
+	if err := m.SetTag(other, "player"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if found, _ := m.Find("player"); found != other {
+		t.Fatalf("expected tag to move to %v, found %v", other, found)
+	}
+}
+
+func TestClearTags(t *testing.T) {
+	m, entities := newTestManager()
+	e := entities.Create()
+
+	_ = m.SetTag(e, "player")
+	_ = m.SetTag(e, "hero")
+	m.ClearTags(e)
+
+	if tags := m.Tags(e); len(tags) != 0 {
+		t.Fatalf("expected no tags, have %v", tags)
+	}
+	for _, tag := range []string{"player", "hero"} {
+		if _, ok := m.Find(tag); ok {
+			t.Fatalf("expected %q to be freed", tag)
+		}
+	}
+}
+
+func TestEntityRemovalClearsTags(t *testing.T) {
+	m, entities := newTestManager()
+	e := entities.Create()
+
+	_ = m.SetTag(e, "player")
+	entities.Remove(e)
+
+	if _, ok := m.Find("player"); ok {
+		t.Fatalf("expected tag of removed entity to be freed")
+	}
+	if m.HasTag(e, "player") {
+		t.Fatalf("expected stale handle to be untagged")
+	}
+
+	// A new entity may reuse the ID but must not inherit the tags
+	reused := entities.Create()
+	if m.HasTag(reused, "player") || len(m.Tags(reused)) != 0 {
+		t.Fatalf("expected new entity %v to be untagged, have %v", reused, m.Tags(reused))
+	}
+	if err := m.SetTag(reused, "player"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+type changeCounter int
+
+func (c *changeCounter) OnEntityChanged(e entity.EntityChangedEvent) { *c++ }
+
+func TestTagChangesNotify(t *testing.T) {
+	eventManager := event.NewManager()
+	entities := entity.NewManager(eventManager)
+	m := NewManager(entities, eventManager)
+
+	var changes changeCounter
+	entity.NewEntityChangedEventManager(eventManager).AddListener(&changes)
+
+	e := entities.Create()
+	_ = m.SetTag(e, "player")
+	_ = m.SetTag(e, "player") // no-op
+	m.RemoveTag(e, "missing") // no-op
+	m.RemoveTag(e, "player")
+	m.ClearTags(e) // no-op
+	_ = m.SetTag(e, "hero")
+	m.ClearTags(e)
+
+	if changes != 4 {
+		t.Fatalf("expected 4 change events, have %d", changes)
+	}
+}
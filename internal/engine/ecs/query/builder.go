@@ -0,0 +1,86 @@
+package query
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/group"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/tag"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// Builder composes component, tag, and group matchers into a single entity.Matcher.
+// An entity matches the built query when it matches every All term, at least one
+// Any term (if any were given), and no None term.
+type Builder struct {
+	componentManager *component.Manager
+	tagManager       *tag.Manager
+	groupManager     *group.Manager
+	all              []entity.Matcher
+	any              []entity.Matcher
+	none             []entity.Matcher
+}
+
+// Term resolves to a matcher against the managers of the builder it is added to.
+type Term func(b *Builder) entity.Matcher
+
+func NewBuilder(componentManager *component.Manager, tagManager *tag.Manager, groupManager *group.Manager) *Builder {
+	return &Builder{
+		componentManager: componentManager,
+		tagManager:       tagManager,
+		groupManager:     groupManager,
+	}
+}
+
+func Component(componentType component.ComponentType) Term {
+	return func(b *Builder) entity.Matcher {
+		return component.NewEntityMatcher(b.componentManager, componentType)
+	}
+}
+
+func Tag(t string) Term {
+	return func(b *Builder) entity.Matcher {
+		return tag.NewEntityMatcher(b.tagManager, t)
+	}
+}
+
+func Group(g string) Term {
+	return func(b *Builder) entity.Matcher {
+		return group.NewEntityMatcher(b.groupManager, g)
+	}
+}
+
+func (b *Builder) All(terms ...Term) *Builder {
+	b.all = append(b.all, b.resolve(terms)...)
+	return b
+}
+
+func (b *Builder) Any(terms ...Term) *Builder {
+	b.any = append(b.any, b.resolve(terms)...)
+	return b
+}
+
+func (b *Builder) None(terms ...Term) *Builder {
+	b.none = append(b.none, b.resolve(terms)...)
+	return b
+}
+
+func (b *Builder) Matcher() entity.Matcher {
+	return &queryMatcher{
+		all:  append([]entity.Matcher(nil), b.all...),
+		any:  append([]entity.Matcher(nil), b.any...),
+		none: append([]entity.Matcher(nil), b.none...),
+	}
+}
+
+func (b *Builder) Collection(eventManager *event.Manager) *entity.Collection {
+	return entity.NewCollection(b.Matcher(), eventManager)
+}
+
+func (b *Builder) resolve(terms []Term) []entity.Matcher {
+	matchers := make([]entity.Matcher, 0, len(terms))
+	for _, term := range terms {
+		matchers = append(matchers, term(b))
+	}
+
+	return matchers
+}
@@ -0,0 +1,114 @@
+package query
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+type Tuple1[C1 any] struct {
+	Entity entity.Entity
+	C1     C1
+}
+
+type Tuple2[C1, C2 any] struct {
+	Entity entity.Entity
+	C1     C1
+	C2     C2
+}
+
+type Tuple3[C1, C2, C3 any] struct {
+	Entity entity.Entity
+	C1     C1
+	C2     C2
+	C3     C3
+}
+
+// Join1 returns the entities of the collection paired with their components. Entities
+// missing the component are skipped.
+func Join1[
+	C1 component.Component[T1], T1 component.ComponentType,
+](
+	collection *entity.Collection,
+	m1 *component.TypedManager[C1, T1],
+) []Tuple1[C1] {
+	entities := collection.Entities()
+	tuples := make([]Tuple1[C1], 0, len(entities))
+
+	for _, e := range entities {
+		c1, ok := m1.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		tuples = append(tuples, Tuple1[C1]{Entity: e, C1: c1})
+	}
+
+	return tuples
+}
+
+// Join2 returns the entities of the collection paired with their components. Entities
+// missing any of the components are skipped.
+func Join2[
+	C1 component.Component[T1], T1 component.ComponentType,
+	C2 component.Component[T2], T2 component.ComponentType,
+](
+	collection *entity.Collection,
+	m1 *component.TypedManager[C1, T1],
+	m2 *component.TypedManager[C2, T2],
+) []Tuple2[C1, C2] {
+	entities := collection.Entities()
+	tuples := make([]Tuple2[C1, C2], 0, len(entities))
+
+	for _, e := range entities {
+		c1, ok := m1.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		c2, ok := m2.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		tuples = append(tuples, Tuple2[C1, C2]{Entity: e, C1: c1, C2: c2})
+	}
+
+	return tuples
+}
+
+// Join3 returns the entities of the collection paired with their components. Entities
+// missing any of the components are skipped.
+func Join3[
+	C1 component.Component[T1], T1 component.ComponentType,
+	C2 component.Component[T2], T2 component.ComponentType,
+	C3 component.Component[T3], T3 component.ComponentType,
+](
+	collection *entity.Collection,
+	m1 *component.TypedManager[C1, T1],
+	m2 *component.TypedManager[C2, T2],
+	m3 *component.TypedManager[C3, T3],
+) []Tuple3[C1, C2, C3] {
+	entities := collection.Entities()
+	tuples := make([]Tuple3[C1, C2, C3], 0, len(entities))
+
+	for _, e := range entities {
+		c1, ok := m1.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		c2, ok := m2.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		c3, ok := m3.GetComponent(e)
+		if !ok {
+			continue
+		}
+
+		tuples = append(tuples, Tuple3[C1, C2, C3]{Entity: e, C1: c1, C2: c2, C3: c3})
+	}
+
+	return tuples
+}
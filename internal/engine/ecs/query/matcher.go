@@ -0,0 +1,35 @@
+package query
+
+import "github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+
+type queryMatcher struct {
+	all  []entity.Matcher
+	any  []entity.Matcher
+	none []entity.Matcher
+}
+
+func (m *queryMatcher) Matches(e entity.Entity) bool {
+	for _, matcher := range m.all {
+		if !matcher.Matches(e) {
+			return false
+		}
+	}
+
+	for _, matcher := range m.none {
+		if matcher.Matches(e) {
+			return false
+		}
+	}
+
+	if len(m.any) == 0 {
+		return true
+	}
+
+	for _, matcher := range m.any {
+		if matcher.Matches(e) {
+			return true
+		}
+	}
+
+	return false
+}
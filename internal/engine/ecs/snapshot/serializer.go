@@ -0,0 +1,210 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/group"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/tag"
+)
+
+// FormatVersion is bumped whenever the layout of a Snapshot changes incompatibly.
+const FormatVersion = 1
+
+type Snapshot struct {
+	Version  int
+	Entities []EntitySnapshot
+}
+
+type EntitySnapshot struct {
+	ID         int64
+	Tag        string                     `json:",omitempty"` // single tag written before entities could carry several
+	Tags       []string                   `json:",omitempty"`
+	Groups     []string                   `json:",omitempty"`
+	Components map[string]json.RawMessage `json:",omitempty"`
+}
+
+// Serializer captures and restores the entities of a world along with their tags, groups,
+// and any components whose types have been registered. Components of unregistered types
+// are not persisted.
+type Serializer struct {
+	entityManager *entity.Manager
+	tagManager    *tag.Manager
+	groupManager  *group.Manager
+	codecs        []codec
+	codecsByName  map[string]codec
+	filter        func(e entity.Entity) bool
+}
+
+type SerializerOption func(*Serializer)
+
+// WithFilter restricts Capture to the entities for which the given function returns true.
+// Entities rebuilt by gameplay code on load can be left out this way even when they carry
+// registered components.
+func WithFilter(f func(e entity.Entity) bool) SerializerOption {
+	return func(s *Serializer) { s.filter = f }
+}
+
+type codec struct {
+	name   string
+	encode func(e entity.Entity) (json.RawMessage, bool, error)
+	decode func(e entity.Entity, data json.RawMessage) error
+}
+
+func NewSerializer(entityManager *entity.Manager, tagManager *tag.Manager, groupManager *group.Manager, opts ...SerializerOption) *Serializer {
+	s := &Serializer{
+		entityManager: entityManager,
+		tagManager:    tagManager,
+		groupManager:  groupManager,
+		codecsByName:  map[string]codec{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Register makes components managed by the given typed manager serializable under the
+// given name. The name is written to save files and must remain stable across versions.
+// Components are encoded with encoding/json.
+func Register[C component.Component[T], T component.ComponentType](s *Serializer, name string, manager *component.TypedManager[C, T]) {
+	if _, ok := s.codecsByName[name]; ok {
+		panic(fmt.Sprintf("component %q already registered", name))
+	}
+
+	c := codec{
+		name: name,
+		encode: func(e entity.Entity) (json.RawMessage, bool, error) {
+			component, ok := manager.GetComponent(e)
+			if !ok {
+				return nil, false, nil
+			}
+
+			data, err := json.Marshal(component)
+			return data, true, err
+		},
+		decode: func(e entity.Entity, data json.RawMessage) error {
+			var component C
+			if err := json.Unmarshal(data, &component); err != nil {
+				return err
+			}
+
+			manager.AddComponent(e, component)
+			return nil
+		},
+	}
+
+	s.codecs = append(s.codecs, c)
+	s.codecsByName[name] = c
+}
+
+func (s *Serializer) Capture() (Snapshot, error) {
+	snapshot := Snapshot{Version: FormatVersion}
+
+	for _, e := range s.entityManager.Entities() {
+		if s.filter != nil && !s.filter(e) {
+			continue
+		}
+
+		entitySnapshot := EntitySnapshot{
+			ID:     e.ID,
+			Tags:   s.tagManager.Tags(e),
+			Groups: s.groupManager.Groups(e),
+		}
+
+		for _, c := range s.codecs {
+			data, ok, err := c.encode(e)
+			if err != nil {
+				return Snapshot{}, fmt.Errorf("encoding component %q of entity %d: %w", c.name, e.ID, err)
+			}
+			if !ok {
+				continue
+			}
+
+			if entitySnapshot.Components == nil {
+				entitySnapshot.Components = map[string]json.RawMessage{}
+			}
+			entitySnapshot.Components[c.name] = data
+		}
+
+		// Entities built entirely from unregistered components (e.g. parts recreated by
+		// gameplay code on load) have no state worth saving
+		if len(entitySnapshot.Tags) == 0 && len(entitySnapshot.Groups) == 0 && len(entitySnapshot.Components) == 0 {
+			continue
+		}
+
+		snapshot.Entities = append(snapshot.Entities, entitySnapshot)
+	}
+
+	return snapshot, nil
+}
+
+// Restore creates a new entity for each entity in the snapshot. It is intended to be
+// called on an empty world; entity IDs are not preserved.
+func (s *Serializer) Restore(snapshot Snapshot) error {
+	if snapshot.Version != FormatVersion {
+		return fmt.Errorf("unsupported snapshot version %d (expected %d)", snapshot.Version, FormatVersion)
+	}
+
+	for _, entitySnapshot := range snapshot.Entities {
+		for name := range entitySnapshot.Components {
+			if _, ok := s.codecsByName[name]; !ok {
+				return fmt.Errorf("entity %d has unregistered component %q", entitySnapshot.ID, name)
+			}
+		}
+	}
+
+	for _, entitySnapshot := range snapshot.Entities {
+		if _, err := s.RestoreEntity(entitySnapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RestoreEntity creates a single entity from the given snapshot and returns it.
+func (s *Serializer) RestoreEntity(entitySnapshot EntitySnapshot) (entity.Entity, error) {
+	for name := range entitySnapshot.Components {
+		if _, ok := s.codecsByName[name]; !ok {
+			return entity.Entity{}, fmt.Errorf("entity %d has unregistered component %q", entitySnapshot.ID, name)
+		}
+	}
+
+	e := s.entityManager.Create()
+
+	tags := entitySnapshot.Tags
+	if entitySnapshot.Tag != "" {
+		tags = append([]string{entitySnapshot.Tag}, tags...)
+	}
+
+	for _, tag := range tags {
+		if err := s.tagManager.SetTag(e, tag); err != nil {
+			s.entityManager.Remove(e)
+			return entity.Entity{}, fmt.Errorf("restoring entity %d: %w", entitySnapshot.ID, err)
+		}
+	}
+
+	for _, group := range entitySnapshot.Groups {
+		s.groupManager.AddGroup(e, group)
+	}
+
+	// Decode in registration order so component-added side effects are deterministic
+	for _, c := range s.codecs {
+		data, ok := entitySnapshot.Components[c.name]
+		if !ok {
+			continue
+		}
+
+		if err := c.decode(e, data); err != nil {
+			s.entityManager.Remove(e)
+			return entity.Entity{}, fmt.Errorf("decoding component %q of entity %d: %w", c.name, entitySnapshot.ID, err)
+		}
+	}
+
+	return e, nil
+}
@@ -9,53 +9,153 @@ import (
 type Manager struct {
 	initialized bool
 	layers      []int
-	systems     map[int][]System
+	entries     map[int][]*entry
+	scheduled   map[int][]*entry
+	batches     map[int][][]*entry
+	count       int
+	syncPoints  []SyncPoint
+	frameSyncs  []SyncPoint
+	parallel    bool
 }
 
-func NewManager() *Manager {
-	return &Manager{
-		systems: map[int][]System{},
+// SyncPoint is flushed by the manager after each layer of systems has been processed.
+// Structural changes deferred during a layer become visible to the next one.
+type SyncPoint interface {
+	Flush()
+}
+
+type ManagerOption func(*Manager)
+
+func WithSyncPoint(syncPoint SyncPoint) ManagerOption {
+	return func(m *Manager) { m.syncPoints = append(m.syncPoints, syncPoint) }
+}
+
+// WithFrameSyncPoint flushes the given sync point once after all layers have been
+// processed, e.g. to drain events queued during the frame. Layer sync points are flushed
+// again afterwards so that changes deferred by the frame sync point are applied before the
+// next frame.
+func WithFrameSyncPoint(syncPoint SyncPoint) ManagerOption {
+	return func(m *Manager) { m.frameSyncs = append(m.frameSyncs, syncPoint) }
+}
+
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{
+		entries: map[int][]*entry{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
 
-func (m *Manager) Add(system System, layer int) {
+// Add registers a system in the given layer. Layers are processed in ascending order;
+// within a layer, systems run in registration order unless reordered by Before and After
+// options.
+func (m *Manager) Add(system System, layer int, opts ...Option) {
 	if m.initialized {
 		system.Init()
 	}
 
-	if _, ok := m.systems[layer]; !ok {
+	if _, ok := m.entries[layer]; !ok {
 		m.layers = append(m.layers, layer)
 		sort.Ints(m.layers)
 	}
 
-	m.systems[layer] = append(m.systems[layer], system)
+	m.entries[layer] = append(m.entries[layer], newEntry(system, layer, m.count, opts))
+	m.count++
+	m.scheduled = nil
+	m.batches = nil
 }
 
 func (m *Manager) Init() {
 	for _, layer := range m.layers {
-		for _, s := range m.systems[layer] {
-			s.Init()
+		for _, e := range m.schedule()[layer] {
+			e.system.Init()
 		}
 	}
 
+	m.flush()
 	m.initialized = true
 }
 
 func (m *Manager) Exit() {
 	for _, layer := range m.layers {
-		for _, s := range m.systems[layer] {
-			s.Exit()
+		for _, e := range m.schedule()[layer] {
+			e.system.Exit()
 		}
 	}
 
-	maps.Clear(m.systems)
+	m.flush()
+	maps.Clear(m.entries)
 	m.layers = nil
+	m.scheduled = nil
+	m.batches = nil
 }
 
 func (m *Manager) Process(elapsedMs int64) {
 	for _, layer := range m.layers {
-		for _, s := range m.systems[layer] {
-			s.Process(elapsedMs)
+		if m.parallel {
+			for _, batch := range m.batch()[layer] {
+				processBatch(batch, elapsedMs)
+			}
+		} else {
+			for _, e := range m.schedule()[layer] {
+				e.process(elapsedMs)
+			}
+		}
+
+		m.flush()
+	}
+
+	if len(m.frameSyncs) > 0 {
+		for _, syncPoint := range m.frameSyncs {
+			syncPoint.Flush()
 		}
+
+		m.flush()
+	}
+}
+
+// Timings returns the most recent processing time of each system in schedule order.
+func (m *Manager) Timings() []Timing {
+	var timings []Timing
+	for _, layer := range m.layers {
+		for _, e := range m.schedule()[layer] {
+			timings = append(timings, Timing{
+				Name:    e.name,
+				Layer:   e.layer,
+				Last:    e.last,
+				Average: e.average,
+			})
+		}
+	}
+
+	return timings
+}
+
+func (m *Manager) schedule() map[int][]*entry {
+	if m.scheduled == nil {
+		m.scheduled = schedule(m.layers, m.entries)
+	}
+
+	return m.scheduled
+}
+
+func (m *Manager) batch() map[int][][]*entry {
+	if m.batches == nil {
+		m.batches = map[int][][]*entry{}
+		for layer, entries := range m.schedule() {
+			m.batches[layer] = batch(entries)
+		}
+	}
+
+	return m.batches
+}
+
+func (m *Manager) flush() {
+	for _, syncPoint := range m.syncPoints {
+		syncPoint.Flush()
 	}
 }
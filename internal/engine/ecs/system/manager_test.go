@@ -0,0 +1,115 @@
+package system_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/command"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/group"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/tag"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+type (
+	componentA struct{}
+	componentB struct{}
+	componentX struct{}
+)
+
+type funcSystem func(elapsedMs int64)
+
+func (f funcSystem) Init()                   {}
+func (f funcSystem) Exit()                   {}
+func (f funcSystem) Process(elapsedMs int64) { f(elapsedMs) }
+
+type (
+	tickEvent        struct{}
+	tickListener     interface{ OnTick(e tickEvent) }
+	tickEventManager = event.TypedManager[tickEvent, tickEventType, tickListener]
+
+	tickEventType struct{}
+)
+
+func (e tickEvent) EventType() tickEventType { return tickEventType{} }
+func (e tickEvent) Notify(l tickListener)    { l.OnTick(e) }
+
+type tickCounter int
+
+func (c *tickCounter) OnTick(e tickEvent) { *c++ }
+
+// TestParallelExecution runs systems that share a collection, a command buffer and an
+// event queue in parallel. Run it with -race: systems with conflicting access share state
+// without synchronization, so batching them together is reported as a data race.
+func TestParallelExecution(t *testing.T) {
+	eventManager := event.NewManager()
+	entityManager := entity.NewManager(eventManager)
+	groupManager := group.NewManager(entityManager, eventManager)
+	tagManager := tag.NewManager(entityManager, eventManager)
+	buffer := command.NewBuffer(entityManager, tagManager, groupManager)
+	spawned := entity.NewCollection(group.NewEntityMatcher(groupManager, "spawned"), eventManager)
+
+	var ticks tickCounter
+	ticker := event.NewTypedManager[tickEvent](eventManager)
+	ticker.AddListener(&ticks)
+
+	m := system.NewManager(
+		system.WithSyncPoint(buffer),
+		system.WithFrameSyncPoint(eventManager),
+		system.WithParallelExecution(),
+	)
+
+	// Non-conflicting systems wait for each other, so they must run concurrently. Each
+	// reads the shared collection, records a command and enqueues an event.
+	frame := 0
+	aReady, bReady := make(chan struct{}, 1), make(chan struct{}, 1)
+	spawner := func(name string, ready, other chan struct{}) funcSystem {
+		return func(int64) {
+			ready <- struct{}{}
+			select {
+			case <-other:
+			case <-time.After(5 * time.Second):
+				t.Errorf("%s did not run concurrently with its batch", name)
+			}
+
+			if n := len(spawned.Entities()); n != 2*frame {
+				t.Errorf("%s saw %d spawned entities in frame %d", name, n, frame)
+			}
+
+			buffer.Create(func(e entity.Entity) { groupManager.AddGroup(e, "spawned") })
+			ticker.Enqueue(tickEvent{})
+		}
+	}
+
+	// Conflicting systems share an unguarded counter
+	var counter int
+	writer := funcSystem(func(int64) { counter++ })
+	reader := funcSystem(func(int64) {
+		if counter != 2*frame+1 {
+			t.Errorf("reader saw counter %d in frame %d", counter, frame)
+		}
+	})
+	undeclared := funcSystem(func(int64) { counter++ })
+
+	m.Add(spawner("a", aReady, bReady), 0, system.Named("a"), system.Reads(componentA{}))
+	m.Add(spawner("b", bReady, aReady), 0, system.Named("b"), system.Reads(componentB{}))
+	m.Add(writer, 0, system.Named("writer"), system.Writes(componentX{}))
+	m.Add(reader, 0, system.Named("reader"), system.Reads(componentX{}))
+	m.Add(undeclared, 0, system.Named("undeclared"))
+	m.Init()
+
+	for frame = 0; frame < 100; frame++ {
+		m.Process(16)
+
+		if n := spawned.Len(); n != 2*(frame+1) {
+			t.Fatalf("expected %d spawned entities after frame %d, have %d", 2*(frame+1), frame, n)
+		}
+		if int(ticks) != 2*(frame+1) {
+			t.Fatalf("expected %d ticks after frame %d, have %d", 2*(frame+1), frame, ticks)
+		}
+		if counter != 2*(frame+1) {
+			t.Fatalf("expected counter %d after frame %d, have %d", 2*(frame+1), frame, counter)
+		}
+	}
+}
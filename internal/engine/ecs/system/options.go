@@ -0,0 +1,81 @@
+package system
+
+import (
+	"fmt"
+	"time"
+)
+
+// Option declares how a system relates to other systems registered with the same manager.
+type Option func(*entry)
+
+// Named gives the system a unique name that other systems can order themselves against
+// and that is reported in timings.
+func Named(name string) Option {
+	return func(e *entry) { e.name = name; e.named = true }
+}
+
+// Reads declares the component types the system reads but does not modify.
+func Reads(componentTypes ...any) Option {
+	return func(e *entry) { e.reads = append(e.reads, componentTypes...) }
+}
+
+// Writes declares the component types the system modifies.
+func Writes(componentTypes ...any) Option {
+	return func(e *entry) { e.writes = append(e.writes, componentTypes...) }
+}
+
+// Before requires the system to run before the named systems.
+func Before(names ...string) Option {
+	return func(e *entry) { e.before = append(e.before, names...) }
+}
+
+// After requires the system to run after the named systems.
+func After(names ...string) Option {
+	return func(e *entry) { e.after = append(e.after, names...) }
+}
+
+type entry struct {
+	system  System
+	layer   int
+	index   int // registration order, used to break ties
+	name    string
+	named   bool
+	reads   []any
+	writes  []any
+	before  []string
+	after   []string
+	last    time.Duration
+	average time.Duration
+}
+
+func newEntry(system System, layer, index int, opts []Option) *entry {
+	e := &entry{
+		system: system,
+		layer:  layer,
+		index:  index,
+		name:   fmt.Sprintf("%T", system),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Timing reports how long a system took to process.
+type Timing struct {
+	Name    string
+	Layer   int
+	Last    time.Duration
+	Average time.Duration // exponentially weighted over recent frames
+}
+
+const timingSmoothing = 0.1
+
+func (e *entry) process(elapsedMs int64) {
+	start := time.Now()
+	e.system.Process(elapsedMs)
+	e.last = time.Since(start)
+	e.average += time.Duration(timingSmoothing * float64(e.last-e.average))
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"slices"
+	"sync"
+)
+
+// WithParallelExecution lets systems of the same layer run concurrently when they cannot
+// interfere with one another. Systems are grouped into batches in schedule order; a batch
+// ends when the next system conflicts with any system already in it. Two systems conflict
+// when either writes a component type the other reads or writes, when an ordering
+// constraint relates them, or when either has not declared its component access at all.
+//
+// Systems that touch the rendering context must not be added to a parallel manager.
+func WithParallelExecution() ManagerOption {
+	return func(m *Manager) { m.parallel = true }
+}
+
+func batch(entries []*entry) [][]*entry {
+	var batches [][]*entry
+	var current []*entry
+
+	for _, e := range entries {
+		for _, other := range current {
+			if e.conflictsWith(other) {
+				batches = append(batches, current)
+				current = nil
+				break
+			}
+		}
+
+		current = append(current, e)
+	}
+
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+func processBatch(entries []*entry, elapsedMs int64) {
+	if len(entries) == 1 {
+		entries[0].process(elapsedMs)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range entries[1:] {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			e.process(elapsedMs)
+		}(e)
+	}
+
+	// Keep one system on the calling goroutine
+	entries[0].process(elapsedMs)
+	wg.Wait()
+}
+
+func (e *entry) declaresAccess() bool {
+	return len(e.reads) > 0 || len(e.writes) > 0
+}
+
+func (e *entry) conflictsWith(other *entry) bool {
+	if !e.declaresAccess() || !other.declaresAccess() {
+		return true
+	}
+
+	if e.orderedWith(other) || other.orderedWith(e) {
+		return true
+	}
+
+	for _, componentType := range e.writes {
+		if slices.Contains(other.reads, componentType) || slices.Contains(other.writes, componentType) {
+			return true
+		}
+	}
+
+	for _, componentType := range other.writes {
+		if slices.Contains(e.reads, componentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *entry) orderedWith(other *entry) bool {
+	return other.named && (slices.Contains(e.before, other.name) || slices.Contains(e.after, other.name))
+}
@@ -0,0 +1,131 @@
+package system
+
+import (
+	"slices"
+	"testing"
+)
+
+type (
+	componentA struct{}
+	componentB struct{}
+	componentX struct{}
+)
+
+type funcSystem func(elapsedMs int64)
+
+func (f funcSystem) Init()                   {}
+func (f funcSystem) Exit()                   {}
+func (f funcSystem) Process(elapsedMs int64) { f(elapsedMs) }
+
+var noop = funcSystem(func(int64) {})
+
+func TestBatch(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		options  [][]Option
+		expected [][]string
+	}{
+		{
+			name: "disjoint access",
+			options: [][]Option{
+				{Named("a"), Writes(componentA{})},
+				{Named("b"), Writes(componentB{})},
+				{Named("x"), Reads(componentX{})},
+			},
+			expected: [][]string{{"a", "b", "x"}},
+		},
+		{
+			name: "shared reads",
+			options: [][]Option{
+				{Named("a"), Reads(componentX{})},
+				{Named("b"), Reads(componentX{}, componentA{})},
+			},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "write after read",
+			options: [][]Option{
+				{Named("a"), Reads(componentX{})},
+				{Named("b"), Writes(componentX{})},
+				{Named("c"), Reads(componentA{})},
+			},
+			expected: [][]string{{"a"}, {"b", "c"}},
+		},
+		{
+			name: "read after write",
+			options: [][]Option{
+				{Named("a"), Writes(componentX{})},
+				{Named("b"), Reads(componentX{})},
+			},
+			expected: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "write after write",
+			options: [][]Option{
+				{Named("a"), Writes(componentX{})},
+				{Named("b"), Writes(componentX{})},
+			},
+			expected: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "ordered",
+			options: [][]Option{
+				{Named("a"), Reads(componentA{})},
+				{Named("b"), Reads(componentB{}), After("a")},
+			},
+			expected: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "undeclared",
+			options: [][]Option{
+				{Named("a"), Reads(componentA{})},
+				{Named("u")},
+				{Named("b"), Reads(componentB{})},
+				{Named("v")},
+				{Named("w")},
+			},
+			expected: [][]string{{"a"}, {"u"}, {"b"}, {"v"}, {"w"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewManager(WithParallelExecution())
+			for _, opts := range test.options {
+				m.Add(noop, 0, opts...)
+			}
+
+			if names := batchNames(m.batch()[0]); !slices.EqualFunc(names, test.expected, slices.Equal[[]string]) {
+				t.Fatalf("unexpected batches. want=%v have=%v", test.expected, names)
+			}
+		})
+	}
+}
+
+func TestBatchNeverIncludesUndeclaredSystems(t *testing.T) {
+	m := NewManager(WithParallelExecution())
+	m.Add(noop, 0, Named("a"), Reads(componentA{}))
+	m.Add(noop, 0, Named("b"), Reads(componentB{}))
+	m.Add(noop, 0, Named("u"))
+	m.Add(noop, 0, Named("c"), Reads(componentA{}))
+	m.Add(noop, 0, Named("v"))
+
+	for _, batch := range m.batch()[0] {
+		for _, e := range batch {
+			if !e.declaresAccess() && len(batch) != 1 {
+				t.Fatalf("undeclared system %q batched with %v", e.name, batchNames([][]*entry{batch}))
+			}
+		}
+	}
+}
+
+func batchNames(batches [][]*entry) [][]string {
+	names := make([][]string, 0, len(batches))
+	for _, batch := range batches {
+		var batchNames []string
+		for _, e := range batch {
+			batchNames = append(batchNames, e.name)
+		}
+		names = append(names, batchNames)
+	}
+
+	return names
+}
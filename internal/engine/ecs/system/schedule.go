@@ -0,0 +1,98 @@
+package system
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schedule orders the systems of each layer so that every before/after constraint between
+// systems of that layer is satisfied. Systems without a constraint between them keep their
+// registration order. Constraints against systems of other layers must agree with layer
+// order; constraints naming unknown systems are ignored.
+func schedule(layers []int, entries map[int][]*entry) map[int][]*entry {
+	byName := map[string]*entry{}
+	for _, layer := range layers {
+		for _, e := range entries[layer] {
+			if !e.named {
+				continue
+			}
+			if _, ok := byName[e.name]; ok {
+				panic(fmt.Sprintf("duplicate system name %q", e.name))
+			}
+
+			byName[e.name] = e
+		}
+	}
+
+	scheduled := make(map[int][]*entry, len(layers))
+	for _, layer := range layers {
+		scheduled[layer] = scheduleLayer(entries[layer], byName)
+	}
+
+	return scheduled
+}
+
+func scheduleLayer(entries []*entry, byName map[string]*entry) []*entry {
+	successors := map[*entry][]*entry{}
+	inDegree := map[*entry]int{}
+
+	addEdge := func(from, to *entry) {
+		if from.layer != to.layer {
+			if from.layer > to.layer {
+				panic(fmt.Sprintf("system %q must run before %q but is in a later layer", from.name, to.name))
+			}
+
+			return
+		}
+
+		successors[from] = append(successors[from], to)
+		inDegree[to]++
+	}
+
+	for _, e := range entries {
+		for _, name := range e.after {
+			if other, ok := byName[name]; ok {
+				addEdge(other, e)
+			}
+		}
+		for _, name := range e.before {
+			if other, ok := byName[name]; ok {
+				addEdge(e, other)
+			}
+		}
+	}
+
+	ordered := make([]*entry, 0, len(entries))
+	done := map[*entry]bool{}
+
+	for len(ordered) < len(entries) {
+		// Pick the earliest registered system whose predecessors have all been scheduled
+		var next *entry
+		for _, e := range entries {
+			if !done[e] && inDegree[e] == 0 {
+				next = e
+				break
+			}
+		}
+
+		if next == nil {
+			var names []string
+			for _, e := range entries {
+				if !done[e] {
+					names = append(names, e.name)
+				}
+			}
+
+			panic(fmt.Sprintf("cyclic system ordering constraints between %s", strings.Join(names, ", ")))
+		}
+
+		done[next] = true
+		ordered = append(ordered, next)
+
+		for _, successor := range successors[next] {
+			inDegree[successor]--
+		}
+	}
+
+	return ordered
+}
@@ -1,11 +1,106 @@
 package event
 
+import (
+	"slices"
+	"sync"
+)
+
+// Manager owns the listeners of all event types along with the queue of events whose
+// dispatch has been deferred. Listeners with a higher priority are notified first;
+// listeners of equal priority are notified in registration order.
 type Manager struct {
-	listeners map[EventType][]any
+	listeners map[EventType][]*registration
+	nextID    uint64
+	mu        sync.Mutex
+	queue     []func()
+}
+
+type registration struct {
+	id       uint64
+	priority int
+	listener any
+	removed  bool
+}
+
+// ListenerHandle identifies a registered listener so that it can later be removed.
+type ListenerHandle struct {
+	eventType EventType
+	id        uint64
+}
+
+type ListenerOption func(*registration)
+
+// WithPriority changes the order in which the listener is notified relative to other
+// listeners of the same event type. The default priority is zero.
+func WithPriority(priority int) ListenerOption {
+	return func(r *registration) { r.priority = priority }
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		listeners: map[EventType][]any{},
+		listeners: map[EventType][]*registration{},
 	}
 }
+
+// RemoveListener stops the listener identified by the given handle from receiving further
+// events, including events currently being dispatched. Removing a listener twice is a no-op.
+func (m *Manager) RemoveListener(handle ListenerHandle) {
+	registrations := m.listeners[handle.eventType]
+
+	index := slices.IndexFunc(registrations, func(r *registration) bool { return r.id == handle.id })
+	if index < 0 {
+		return
+	}
+
+	registrations[index].removed = true
+	m.listeners[handle.eventType] = slices.Delete(slices.Clone(registrations), index, index+1)
+}
+
+// Flush dispatches queued events in the order they were enqueued. Events enqueued by
+// listeners during the flush are dispatched in the same flush. It satisfies
+// system.SyncPoint.
+func (m *Manager) Flush() {
+	for {
+		m.mu.Lock()
+		queue := m.queue
+		m.queue = nil
+		m.mu.Unlock()
+
+		if len(queue) == 0 {
+			return
+		}
+
+		for _, dispatch := range queue {
+			dispatch()
+		}
+	}
+}
+
+func (m *Manager) addListener(eventType EventType, listener any, opts []ListenerOption) ListenerHandle {
+	m.nextID++
+	r := &registration{id: m.nextID, listener: listener}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	// Insert after every listener of equal or higher priority. The slice is replaced rather
+	// than modified so that in-progress dispatches are unaffected.
+	registrations := m.listeners[eventType]
+	index := len(registrations)
+	for i, other := range registrations {
+		if other.priority < r.priority {
+			index = i
+			break
+		}
+	}
+
+	m.listeners[eventType] = slices.Insert(slices.Clone(registrations), index, r)
+	return ListenerHandle{eventType: eventType, id: r.id}
+}
+
+func (m *Manager) enqueue(dispatch func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queue = append(m.queue, dispatch)
+}
@@ -10,15 +10,26 @@ func NewTypedManager[E Event[T, L], T EventType, L any](manager *Manager) *Typed
 	}
 }
 
-func (r *TypedManager[E, T, L]) AddListener(listener L) {
+// AddListener registers a listener and returns a handle that can be passed to
+// RemoveListener.
+func (r *TypedManager[E, T, L]) AddListener(listener L, opts ...ListenerOption) ListenerHandle {
 	var e E
 	eventType := e.EventType() // Infer event type value from type param
-	r.manager.listeners[eventType] = append(r.manager.listeners[eventType], listener)
+	return r.manager.addListener(eventType, listener, opts)
 }
 
+func (r *TypedManager[E, T, L]) RemoveListener(handle ListenerHandle) {
+	r.manager.RemoveListener(handle)
+}
+
+// Dispatch notifies all listeners of the event immediately.
 func (r *TypedManager[E, T, L]) Dispatch(event E) {
-	for _, listener := range r.manager.listeners[event.EventType()] {
-		listenerL, ok := listener.(L)
+	for _, registration := range r.manager.listeners[event.EventType()] {
+		if registration.removed {
+			continue
+		}
+
+		listenerL, ok := registration.listener.(L)
 		if !ok {
 			panic("Listener type mismatch")
 		}
@@ -26,3 +37,9 @@ func (r *TypedManager[E, T, L]) Dispatch(event E) {
 		event.Notify(listenerL)
 	}
 }
+
+// Enqueue defers dispatch of the event until the next call to Flush on the underlying
+// manager. Events may be enqueued concurrently.
+func (r *TypedManager[E, T, L]) Enqueue(event E) {
+	r.manager.enqueue(func() { r.Dispatch(event) })
+}
@@ -0,0 +1,19 @@
+package hierarchy
+
+import "github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+
+// HierarchyComponent attaches an entity to a parent. Local is owned by gameplay code;
+// World is recomputed from the parent chain each frame by the hierarchy system.
+type HierarchyComponent struct {
+	Parent entity.Entity
+	Local  Transform
+	World  Transform
+}
+
+type HierarchyComponentType struct{}
+
+var hierarchyComponentType = HierarchyComponentType{}
+
+func (c *HierarchyComponent) ComponentType() HierarchyComponentType {
+	return hierarchyComponentType
+}
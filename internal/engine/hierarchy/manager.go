@@ -0,0 +1,161 @@
+package hierarchy
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// Manager maintains parent/child relationships between entities. Removing an entity
+// removes all of its descendants.
+type Manager struct {
+	entityManager             *entity.Manager
+	hierarchyComponentManager *component.TypedManager[*HierarchyComponent, HierarchyComponentType]
+	parents                   map[entity.Entity]entity.Entity
+	children                  map[entity.Entity][]entity.Entity
+	rootTransform             func(e entity.Entity) (Transform, bool)
+}
+
+type ManagerOption func(*Manager)
+
+// WithRootTransform supplies the world transform of entities that have children but are
+// not themselves attached to a parent, e.g. from a physics body. Roots for which the
+// function returns false are placed at the origin.
+func WithRootTransform(f func(e entity.Entity) (Transform, bool)) ManagerOption {
+	return func(m *Manager) { m.rootTransform = f }
+}
+
+func NewManager(entityManager *entity.Manager, eventManager *event.Manager, componentManager *component.Manager, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		entityManager:             entityManager,
+		hierarchyComponentManager: component.NewTypedManager[*HierarchyComponent](componentManager, eventManager),
+		parents:                   map[entity.Entity]entity.Entity{},
+		children:                  map[entity.Entity][]entity.Entity{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	entityRemovedEventManager := entity.NewEntityRemovedEventManager(eventManager)
+	entityRemovedEventManager.AddListener(m)
+	return m
+}
+
+// Attach makes child a child of parent at the given local transform, detaching it from
+// any previous parent. It returns false if either entity is stale. Attaching an entity to
+// itself or to one of its descendants panics.
+func (m *Manager) Attach(child, parent entity.Entity, local Transform) bool {
+	if !m.entityManager.IsAlive(child) || !m.entityManager.IsAlive(parent) {
+		return false
+	}
+
+	for ancestor, ok := parent, true; ok; ancestor, ok = m.Parent(ancestor) {
+		if ancestor == child {
+			panic(fmt.Sprintf("attaching entity %d to entity %d would create a cycle", child.ID, parent.ID))
+		}
+	}
+
+	m.Detach(child)
+	m.parents[child] = parent
+	m.children[parent] = append(m.children[parent], child)
+	m.hierarchyComponentManager.AddComponent(child, &HierarchyComponent{Parent: parent, Local: local})
+	return true
+}
+
+// Detach makes child a root entity again. Its descendants remain attached to it.
+func (m *Manager) Detach(child entity.Entity) {
+	parent, ok := m.Parent(child)
+	if !ok {
+		return
+	}
+
+	m.unlink(parent, child)
+	m.hierarchyComponentManager.RemoveComponent(child)
+}
+
+func (m *Manager) Parent(e entity.Entity) (entity.Entity, bool) {
+	parent, ok := m.parents[e]
+	return parent, ok
+}
+
+// Children returns the direct children of the given entity in attachment order. The
+// returned slice must not be modified.
+func (m *Manager) Children(e entity.Entity) []entity.Entity {
+	return m.children[e]
+}
+
+func (m *Manager) GetComponent(e entity.Entity) (*HierarchyComponent, bool) {
+	return m.hierarchyComponentManager.GetComponent(e)
+}
+
+// World returns the world transform of an entity as of the last propagation.
+func (m *Manager) World(e entity.Entity) Transform {
+	if hierarchyComponent, ok := m.hierarchyComponentManager.GetComponent(e); ok {
+		return hierarchyComponent.World
+	}
+
+	return m.root(e)
+}
+
+func (m *Manager) OnEntityRemoved(e entity.EntityRemovedEvent) {
+	if parent, ok := m.Parent(e.Entity); ok {
+		m.unlink(parent, e.Entity)
+	}
+
+	children := m.children[e.Entity]
+	delete(m.children, e.Entity)
+
+	for _, child := range children {
+		m.entityManager.Remove(child)
+	}
+}
+
+// propagate recomputes the world transforms of all descendants of root entities.
+func (m *Manager) propagate() {
+	for parent := range m.children {
+		if _, ok := m.Parent(parent); !ok {
+			m.propagateFrom(parent, m.root(parent))
+		}
+	}
+}
+
+func (m *Manager) propagateFrom(parent entity.Entity, world Transform) {
+	for _, child := range m.children[parent] {
+		hierarchyComponent, ok := m.hierarchyComponentManager.GetComponent(child)
+		if !ok {
+			continue
+		}
+
+		hierarchyComponent.World = world.Apply(hierarchyComponent.Local)
+		m.propagateFrom(child, hierarchyComponent.World)
+	}
+}
+
+func (m *Manager) root(e entity.Entity) Transform {
+	if m.rootTransform != nil {
+		if transform, ok := m.rootTransform(e); ok {
+			return transform
+		}
+	}
+
+	return Transform{}
+}
+
+func (m *Manager) unlink(parent, child entity.Entity) {
+	delete(m.parents, child)
+
+	children := m.children[parent]
+	if index := slices.Index(children, child); index >= 0 {
+		children = slices.Delete(slices.Clone(children), index, index+1)
+	}
+
+	if len(children) == 0 {
+		delete(m.children, parent)
+	} else {
+		m.children[parent] = children
+	}
+}
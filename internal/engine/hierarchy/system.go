@@ -0,0 +1,20 @@
+package hierarchy
+
+import "github.com/efritz/lunar-fever/internal/engine/ecs/system"
+
+type hierarchySystem struct {
+	manager *Manager
+}
+
+// NewHierarchySystem propagates transforms from parents to children. It should run after
+// every system that moves root entities or modifies local transforms.
+func NewHierarchySystem(manager *Manager) system.System {
+	return &hierarchySystem{manager: manager}
+}
+
+func (s *hierarchySystem) Init() {}
+func (s *hierarchySystem) Exit() {}
+
+func (s *hierarchySystem) Process(elapsedMs int64) {
+	s.manager.propagate()
+}
@@ -0,0 +1,23 @@
+package hierarchy
+
+import "github.com/efritz/lunar-fever/internal/common/math"
+
+// Transform places an entity relative to a frame of reference: the world for root
+// entities, or the parent's world transform for attached entities.
+type Transform struct {
+	Position math.Vector
+	Rotation float32
+}
+
+// Apply returns the world transform of a child with the given local transform when the
+// receiver is the world transform of its parent.
+func (t Transform) Apply(local Transform) Transform {
+	c := math.Cos32(t.Rotation)
+	s := math.Sin32(t.Rotation)
+	rotation := math.Matrix32{M00: c, M01: -s, M10: s, M11: c}
+
+	return Transform{
+		Position: t.Position.Add(rotation.Mul(local.Position)),
+		Rotation: t.Rotation + local.Rotation,
+	}
+}
@@ -6,51 +6,74 @@ import (
 	"github.com/efritz/lunar-fever/internal/common/math"
 )
 
+// BodyType decides how a body responds to the world.
+type BodyType int
+
+const (
+	// BodyDynamic bodies are moved by forces, impulses, joints and contacts. A dynamic body
+	// whose fixtures have no density and whose mass is not set behaves as a static one.
+	BodyDynamic BodyType = iota
+
+	// BodyStatic bodies never move and are not pushed by anything.
+	BodyStatic
+
+	// BodyKinematic bodies move at their velocity, ignoring forces, and are not pushed by
+	// anything. They push dynamic bodies out of their way.
+	BodyKinematic
+)
+
 type Body struct {
 	Name            string
 	Fixtures        []Fixture
-	inverseMass     float32
+	bodyType        BodyType
+	mass            float32 // from the fixtures unless overridden
+	inertia         float32
+	massOverridden  bool
+	inverseMass     float32 // zero unless the body is dynamic
 	inverseInertia  float32
 	Position        math.Vector
 	LinearVelocity  math.Vector
 	AngularVelocity float32
 	Orient          float32
 	Rotation        math.Matrix32
-	force           math.Vector
+	force           math.Vector // applied over the steps of the current frame
 	torque          float32
+	sleep           sleepState
+	Bullet          bool      // whether the body is swept every step regardless of its speed
+	previous        placement // before the last step, for interpolation
+	stepped         bool
 }
 
+// NewBody creates a dynamic body from the given fixtures. The fixtures are moved so that
+// the center of mass of the body is at its origin.
 func NewBody(name string, fixtures []Fixture) *Body {
-	mass := float32(0)
-	inertia := float32(0)
-	centroid := math.Vector{}
+	mass, inertia, centroid := massData(fixtures)
 
 	for _, fixture := range fixtures {
-		fixtureCentroid := math.Vector{}
-		fixtureArea := float32(0)
-		fixtureInertia := float32(0)
-
-		for i, p1 := range fixture.Vertices {
-			p2 := fixture.Vertices[(i+1)%len(fixture.Vertices)]
-
-			triangleArea := p1.X*p2.Y - p2.X*p1.Y
-
-			fixtureArea += triangleArea
-			fixtureCentroid = fixtureCentroid.Add(p1.Muls(triangleArea / 6))
-			fixtureCentroid = fixtureCentroid.Add(p2.Muls(triangleArea / 6))
-
-			x2 := p1.X*p1.X + p1.X*p2.X + p2.X*p2.X
-			y2 := p1.Y*p1.Y + p1.Y*p2.Y + p2.Y*p2.Y
-			fixtureInertia += (x2 + y2) * triangleArea
+		for i, v := range fixture.Vertices {
+			fixture.Vertices[i] = v.Sub(centroid)
 		}
+	}
 
-		fixtureArea /= 2
-		fixtureInertia /= 12
+	b := &Body{
+		Name:     name,
+		Fixtures: fixtures,
+		mass:     mass,
+		inertia:  inertia,
+		Rotation: math.Matrix32{M00: 1, M11: 1},
+	}
+	b.updateInverseMass()
+	return b
+}
 
-		fixtureCentroid = fixtureCentroid.Divs(fixtureArea)
+// massData returns the total mass and inertia of the given fixtures along with their
+// center of mass.
+func massData(fixtures []Fixture) (mass, inertia float32, centroid math.Vector) {
+	for _, fixture := range fixtures {
+		fixtureArea, fixtureCentroid, fixtureInertia := fixture.massProperties()
 
-		fixtureMass := fixtureArea * fixture.density
-		fixtureInertia = fixtureInertia * fixture.density
+		fixtureMass := fixtureArea * fixture.Density
+		fixtureInertia = fixtureInertia * fixture.Density
 
 		centroid = centroid.Add(fixtureCentroid.Muls(fixtureMass))
 
@@ -62,48 +85,102 @@ func NewBody(name string, fixtures []Fixture) *Body {
 		centroid = centroid.Divs(mass)
 	}
 
-	inverseMass := float32(0)
-	if mass != 0 {
-		inverseMass = 1 / mass
+	return mass, inertia, centroid
+}
+
+func (b *Body) updateInverseMass() {
+	b.inverseMass, b.inverseInertia = 0, 0
+	if b.bodyType != BodyDynamic {
+		return
 	}
 
-	inverseInertia := float32(0)
-	if inertia != 0 {
-		inverseInertia = 1 / inertia
+	if b.mass != 0 {
+		b.inverseMass = 1 / b.mass
 	}
+	if b.inertia != 0 {
+		b.inverseInertia = 1 / b.inertia
+	}
+}
 
-	for _, fixture := range fixtures {
-		for i, v := range fixture.Vertices {
-			fixture.Vertices[i] = v.Sub(centroid)
-		}
+func (b *Body) Type() BodyType {
+	return b.bodyType
+}
+
+// SetType changes how the body responds to the world. Static bodies are brought to a stop.
+func (b *Body) SetType(bodyType BodyType) {
+	b.bodyType = bodyType
+	b.updateInverseMass()
+
+	if bodyType == BodyStatic {
+		b.LinearVelocity = math.Vector{}
+		b.AngularVelocity = 0
+	}
+
+	b.ClearForces()
+	b.Wake()
+}
+
+// SetMassData overrides the mass and rotational inertia derived from the fixtures of the
+// body. An inertia of zero keeps the body from rotating.
+func (b *Body) SetMassData(mass, inertia float32) {
+	b.mass, b.inertia = mass, inertia
+	b.massOverridden = true
+	b.updateInverseMass()
+}
+
+// ResetMassData derives the mass and rotational inertia of the body from its fixtures again.
+func (b *Body) ResetMassData() {
+	b.mass, b.inertia, _ = massData(b.Fixtures)
+	b.massOverridden = false
+	b.updateInverseMass()
+}
+
+// Mass returns the mass of the body, or zero if the body cannot be pushed.
+func (b *Body) Mass() float32 {
+	if b.inverseMass == 0 {
+		return 0
 	}
 
-	return &Body{
-		Name:           name,
-		Fixtures:       fixtures,
-		inverseMass:    inverseMass,
-		inverseInertia: inverseInertia,
-		Rotation:       math.Matrix32{1, 0, 0, 1},
+	return 1 / b.inverseMass
+}
+
+// Inertia returns the rotational inertia of the body about its center of mass, or zero if
+// the body cannot be turned.
+func (b *Body) Inertia() float32 {
+	if b.inverseInertia == 0 {
+		return 0
 	}
+
+	return 1 / b.inverseInertia
+}
+
+// moves returns true if the body is integrated: it is kinematic, or dynamic with mass.
+func (b *Body) moves() bool {
+	return b.bodyType == BodyKinematic || b.inverseMass != 0
 }
 
-// TODO - why are these unused?
+// SetOrient places the body at the given orientation, keeping its rotation matrix in step.
 func (b *Body) SetOrient(radians float32) {
 	c := math.Cos32(radians)
 	s := math.Sin32(radians)
 
 	b.Orient = radians
-	b.Rotation = math.Matrix32{c, -s, s, c}
+	b.Rotation = math.Matrix32{M00: c, M01: -s, M10: s, M11: c}
 }
 
-// TODO - unused?
+// ApplyForce pushes the body from its center of mass. Forces act over every step taken in
+// the current frame and are cleared once the frame's steps have been taken.
 func (b *Body) ApplyForce(force math.Vector) {
 	b.force = b.force.Add(force)
-	// TODO: if applied at a specific point
-	// this.torque += (point.x - position.x) * force.y - (point.y - position.y) * force.x;
 }
 
-// TODO - unused?
+// ApplyForceAtPoint pushes the body from the given point in world space, which also turns
+// the body unless the force points through its center of mass.
+func (b *Body) ApplyForceAtPoint(force, point math.Vector) {
+	b.force = b.force.Add(force)
+	b.torque += point.Sub(b.Position).Cross(force)
+}
+
 func (b *Body) ApplyTorque(torque float32) {
 	b.torque += torque
 }
@@ -113,6 +190,15 @@ func (b *Body) ClearForces() {
 	b.torque = 0
 }
 
+// ApplyLinearImpulse changes the velocity of the body at once, as if it were struck at the
+// given point in world space.
+func (b *Body) ApplyLinearImpulse(impulse, point math.Vector) {
+	b.ApplyImpulse(impulse, point.Sub(b.Position), false)
+}
+
+// ApplyImpulse changes the velocity of the body at once, as if it were struck at the given
+// offset from its center of mass. The impulse is reversed if negative is set, which suits
+// the solver pushing two bodies apart.
 func (b *Body) ApplyImpulse(impulse math.Vector, contact math.Vector, negative bool) {
 	if negative {
 		impulse = impulse.Neg()
@@ -132,10 +218,10 @@ func (b *Body) NonorientedBound() (x1, y1, x2, y2 float32) {
 		for _, vertex := range fixture.Vertices {
 			v := vertex.Add(b.Position)
 
-			minX = math.Min(minX, v.X)
-			maxX = math.Max(maxX, v.X)
-			minY = math.Min(minY, v.Y)
-			maxY = math.Max(maxY, v.Y)
+			minX = math.Min(minX, v.X-fixture.Radius)
+			maxX = math.Max(maxX, v.X+fixture.Radius)
+			minY = math.Min(minY, v.Y-fixture.Radius)
+			maxY = math.Max(maxY, v.Y+fixture.Radius)
 		}
 	}
 
@@ -152,10 +238,10 @@ func (b *Body) CoverBound() (x1, y1, x2, y2 float32) {
 		for i := range fixture.Vertices {
 			v := fixture.VertexInWorldSpace(b, i)
 
-			minX = math.Min(minX, v.X)
-			maxX = math.Max(maxX, v.X)
-			minY = math.Min(minY, v.Y)
-			maxY = math.Max(maxY, v.Y)
+			minX = math.Min(minX, v.X-fixture.Radius)
+			maxX = math.Max(maxX, v.X+fixture.Radius)
+			minY = math.Min(minY, v.Y-fixture.Radius)
+			maxY = math.Max(maxY, v.Y+fixture.Radius)
 		}
 	}
 
@@ -0,0 +1,243 @@
+package physics
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+type bodyJSON struct {
+	Name            string
+	Type            BodyType `json:",omitempty"`
+	Mass            *float32 `json:",omitempty"` // overrides, derived from the fixtures by default
+	Inertia         *float32 `json:",omitempty"`
+	Fixtures        []fixtureJSON
+	Position        math.Vector
+	LinearVelocity  math.Vector
+	AngularVelocity float32
+	Orient          float32
+	Bullet          bool `json:",omitempty"`
+}
+
+type fixtureJSON struct {
+	Vertices []math.Vector `json:",omitempty"`
+	Radius   float32       `json:",omitempty"`
+	Box      *boxJSON      `json:",omitempty"` // shorthands accepted in hand-written data
+	Circle   *circleJSON   `json:",omitempty"`
+	Capsule  *capsuleJSON  `json:",omitempty"`
+	Category *Category     `json:",omitempty"` // defaults to CategoryDefault
+	Mask     *Category     `json:",omitempty"` // defaults to CategoryAll
+	Sensor   bool          `json:",omitempty"`
+	Material string        `json:",omitempty"` // name of a registered material
+	Density  *float32      `json:",omitempty"` // defaults to the density of the material
+
+	// Fixtures without a registered material describe theirs inline
+	Restitution     float32 `json:",omitempty"`
+	StaticFriction  float32 `json:",omitempty"`
+	DynamicFriction float32 `json:",omitempty"`
+}
+
+// boxJSON mirrors the bounds arguments of NewBasicFixture.
+type boxJSON struct {
+	X, Y, W, H float32
+}
+
+// circleJSON mirrors the arguments of NewCircleFixture.
+type circleJSON struct {
+	X, Y, R float32
+}
+
+// capsuleJSON mirrors the arguments of NewCapsuleFixture.
+type capsuleJSON struct {
+	X1, Y1, X2, Y2, R float32
+}
+
+func (b *Body) MarshalJSON() ([]byte, error) {
+	fixtures := make([]fixtureJSON, 0, len(b.Fixtures))
+	for _, fixture := range b.Fixtures {
+		payload := fixtureJSON{
+			Vertices: fixture.Vertices,
+			Radius:   fixture.Radius,
+			Sensor:   fixture.Sensor,
+		}
+		if material, ok := LookupMaterial(fixture.Material.Name); ok && material == fixture.Material {
+			payload.Material = material.Name
+		} else {
+			payload.Restitution = fixture.Material.Restitution
+			payload.StaticFriction = fixture.Material.StaticFriction
+			payload.DynamicFriction = fixture.Material.DynamicFriction
+		}
+		if density := fixture.Density; payload.Material == "" || density != fixture.Material.Density {
+			payload.Density = &density
+		}
+		if category := fixture.Category; category != CategoryDefault {
+			payload.Category = &category
+		}
+		if mask := fixture.Mask; mask != CategoryAll {
+			payload.Mask = &mask
+		}
+
+		fixtures = append(fixtures, payload)
+	}
+
+	payload := bodyJSON{
+		Name:            b.Name,
+		Type:            b.bodyType,
+		Fixtures:        fixtures,
+		Position:        b.Position,
+		LinearVelocity:  b.LinearVelocity,
+		AngularVelocity: b.AngularVelocity,
+		Orient:          b.Orient,
+		Bullet:          b.Bullet,
+	}
+	if b.massOverridden {
+		payload.Mass, payload.Inertia = &b.mass, &b.inertia
+	}
+
+	return json.Marshal(payload)
+}
+
+// UnmarshalJSON rebuilds the body from its fixtures so that mass and inertia are derived
+// exactly as they are for a body constructed with NewBody.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var payload bodyJSON
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	fixtures := make([]Fixture, 0, len(payload.Fixtures))
+	for _, fixture := range payload.Fixtures {
+		f, err := fixture.build()
+		if err != nil {
+			return fmt.Errorf("fixture of body %q: %w", payload.Name, err)
+		}
+
+		if fixture.Category != nil {
+			f.Category = *fixture.Category
+		}
+		if fixture.Mask != nil {
+			f.Mask = *fixture.Mask
+		}
+		f.Sensor = fixture.Sensor
+
+		fixtures = append(fixtures, f)
+	}
+
+	body := NewBody(payload.Name, fixtures)
+	body.SetType(payload.Type)
+	if payload.Mass != nil || payload.Inertia != nil {
+		mass, inertia := body.mass, body.inertia
+		if payload.Mass != nil {
+			mass = *payload.Mass
+		}
+		if payload.Inertia != nil {
+			inertia = *payload.Inertia
+		}
+
+		body.SetMassData(mass, inertia)
+	}
+	body.Position = payload.Position
+	body.LinearVelocity = payload.LinearVelocity
+	body.AngularVelocity = payload.AngularVelocity
+	body.SetOrient(payload.Orient)
+	body.Bullet = payload.Bullet
+
+	*b = *body
+	return nil
+}
+
+var bodyTypeNames = map[BodyType]string{
+	BodyDynamic:   "dynamic",
+	BodyStatic:    "static",
+	BodyKinematic: "kinematic",
+}
+
+func (t BodyType) MarshalText() ([]byte, error) {
+	name, ok := bodyTypeNames[t]
+	if !ok {
+		return nil, fmt.Errorf("unknown body type %d", t)
+	}
+
+	return []byte(name), nil
+}
+
+func (t *BodyType) UnmarshalText(text []byte) error {
+	for bodyType, name := range bodyTypeNames {
+		if name == string(text) {
+			*t = bodyType
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown body type %q", text)
+}
+
+func (f fixtureJSON) build() (Fixture, error) {
+	material, err := f.material()
+	if err != nil {
+		return Fixture{}, err
+	}
+
+	fixture, err := f.shape(material)
+	if err != nil {
+		return Fixture{}, err
+	}
+
+	if f.Density != nil {
+		fixture.Density = *f.Density
+	}
+
+	return fixture, nil
+}
+
+func (f fixtureJSON) material() (*Material, error) {
+	if f.Material == "" {
+		material := &Material{Restitution: f.Restitution, StaticFriction: f.StaticFriction, DynamicFriction: f.DynamicFriction}
+		if f.Density != nil {
+			material.Density = *f.Density
+		}
+
+		return material, nil
+	}
+
+	material, ok := LookupMaterial(f.Material)
+	if !ok {
+		return nil, fmt.Errorf("unknown material %q", f.Material)
+	}
+
+	return material, nil
+}
+
+func (f fixtureJSON) shape(material *Material) (Fixture, error) {
+	if box := f.Box; box != nil {
+		return NewBasicFixture(box.X, box.Y, box.W, box.H, material), nil
+	}
+
+	if circle := f.Circle; circle != nil {
+		return NewCircleFixture(circle.X, circle.Y, circle.R, material), nil
+	}
+
+	if capsule := f.Capsule; capsule != nil {
+		return NewCapsuleFixture(capsule.X1, capsule.Y1, capsule.X2, capsule.Y2, capsule.R, material), nil
+	}
+
+	if f.Radius > 0 {
+		switch len(f.Vertices) {
+		case 1:
+			v := f.Vertices[0]
+			return NewCircleFixture(v.X, v.Y, f.Radius, material), nil
+		case 2:
+			v1, v2 := f.Vertices[0], f.Vertices[1]
+			return NewCapsuleFixture(v1.X, v1.Y, v2.X, v2.Y, f.Radius, material), nil
+		default:
+			return Fixture{}, fmt.Errorf("rounded fixture has %d vertices", len(f.Vertices))
+		}
+	}
+
+	if len(f.Vertices) < 3 {
+		return Fixture{}, fmt.Errorf("polygon fixture has %d vertices", len(f.Vertices))
+	}
+
+	return NewFixture(f.Vertices, material), nil
+}
@@ -0,0 +1,216 @@
+package physics
+
+import (
+	"cmp"
+	stdmath "math"
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// Broadphase is a uniform grid over the cover bounds of bodies. It yields the pairs of
+// bodies whose cells overlap so that narrowphase tests only run on nearby bodies. Pairs of
+// two bodies that never move are not reported.
+type Broadphase struct {
+	cellSize float32
+	cells    map[cell][]*proxy
+	proxies  map[entity.Entity]*proxy
+	dirty    map[entity.Entity]*Body
+	pairs    []Pair
+}
+
+// Pair is a candidate pair of entities ordered by ID.
+type Pair struct {
+	A, B entity.Entity
+}
+
+type cell struct {
+	x, y int32
+}
+
+type proxy struct {
+	entity entity.Entity
+	body   *Body
+	static bool
+	min    cell
+	max    cell
+}
+
+func NewBroadphase(cellSize float32) *Broadphase {
+	return &Broadphase{
+		cellSize: cellSize,
+		cells:    map[cell][]*proxy{},
+		proxies:  map[entity.Entity]*proxy{},
+		dirty:    map[entity.Entity]*Body{},
+	}
+}
+
+// Update schedules the bounds of the given entity's body to be refreshed before the next
+// call to Pairs. Entities not yet in the broadphase are added.
+func (b *Broadphase) Update(e entity.Entity, body *Body) {
+	b.dirty[e] = body
+}
+
+func (b *Broadphase) Remove(e entity.Entity) {
+	delete(b.dirty, e)
+
+	if p, ok := b.proxies[e]; ok {
+		b.unlink(p)
+		delete(b.proxies, e)
+	}
+}
+
+// Pairs returns the candidate pairs in ascending order. The returned slice is reused by
+// the next call.
+func (b *Broadphase) Pairs() []Pair {
+	b.refresh()
+	b.pairs = b.pairs[:0]
+
+	for _, p := range b.proxies {
+		if p.static {
+			continue
+		}
+
+		for x := p.min.x; x <= p.max.x; x++ {
+			for y := p.min.y; y <= p.max.y; y++ {
+				for _, other := range b.cells[cell{x, y}] {
+					if other == p {
+						continue
+					}
+
+					// Dynamic pairs are seen from both sides; keep one
+					if !other.static && compareEntities(p.entity, other.entity) > 0 {
+						continue
+					}
+
+					// Report each pair only from the first cell the two proxies share
+					if x != max(p.min.x, other.min.x) || y != max(p.min.y, other.min.y) {
+						continue
+					}
+
+					b.pairs = append(b.pairs, newPair(p.entity, other.entity))
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(b.pairs, comparePairs)
+
+	return b.pairs
+}
+
+// Query returns the entities in ascending order whose cells overlap the given bounds.
+// Callers test the returned bodies against the bounds themselves.
+func (b *Broadphase) Query(x1, y1, x2, y2 float32) []entity.Entity {
+	b.refresh()
+
+	min, max := b.cellAt(x1, y1), b.cellAt(x2, y2)
+	seen := map[*proxy]struct{}{}
+	var entities []entity.Entity
+
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			for _, p := range b.cells[cell{x, y}] {
+				if _, ok := seen[p]; !ok {
+					seen[p] = struct{}{}
+					entities = append(entities, p.entity)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(entities, compareEntities)
+	return entities
+}
+
+func (b *Broadphase) refresh() {
+	for e, body := range b.dirty {
+		p, ok := b.proxies[e]
+		if !ok {
+			p = &proxy{entity: e}
+			b.proxies[e] = p
+		} else if p.body == body {
+			// Skip relinking if the body is still covering the same cells
+			if min, max := b.cellRange(body); min == p.min && max == p.max {
+				p.static = !body.moves()
+				continue
+			}
+		}
+
+		b.unlink(p)
+		p.body = body
+		p.static = !body.moves()
+		p.min, p.max = b.cellRange(body)
+		b.link(p)
+	}
+
+	clear(b.dirty)
+}
+
+func (b *Broadphase) link(p *proxy) {
+	for x := p.min.x; x <= p.max.x; x++ {
+		for y := p.min.y; y <= p.max.y; y++ {
+			b.cells[cell{x, y}] = append(b.cells[cell{x, y}], p)
+		}
+	}
+}
+
+func (b *Broadphase) unlink(p *proxy) {
+	if p.body == nil {
+		return
+	}
+
+	for x := p.min.x; x <= p.max.x; x++ {
+		for y := p.min.y; y <= p.max.y; y++ {
+			key := cell{x, y}
+			proxies := b.cells[key]
+
+			if i := slices.Index(proxies, p); i >= 0 {
+				proxies[i] = proxies[len(proxies)-1]
+				proxies = proxies[:len(proxies)-1]
+			}
+
+			if len(proxies) == 0 {
+				delete(b.cells, key)
+			} else {
+				b.cells[key] = proxies
+			}
+		}
+	}
+}
+
+func (b *Broadphase) cellRange(body *Body) (cell, cell) {
+	x1, y1, x2, y2 := body.CoverBound()
+	return b.cellAt(x1, y1), b.cellAt(x2, y2)
+}
+
+func (b *Broadphase) cellAt(x, y float32) cell {
+	return cell{
+		x: int32(stdmath.Floor(float64(x / b.cellSize))),
+		y: int32(stdmath.Floor(float64(y / b.cellSize))),
+	}
+}
+
+func newPair(a, b entity.Entity) Pair {
+	if compareEntities(a, b) > 0 {
+		a, b = b, a
+	}
+
+	return Pair{A: a, B: b}
+}
+
+func comparePairs(p1, p2 Pair) int {
+	if c := compareEntities(p1.A, p2.A); c != 0 {
+		return c
+	}
+
+	return compareEntities(p1.B, p2.B)
+}
+
+func compareEntities(a, b entity.Entity) int {
+	if c := cmp.Compare(a.ID, b.ID); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.Generation, b.Generation)
+}
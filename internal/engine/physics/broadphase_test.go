@@ -0,0 +1,244 @@
+package physics
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+type testBody struct {
+	entity entity.Entity
+	body   *Body
+}
+
+func newTestBox(id int64, x, y, w, h float32, bodyType BodyType) testBody {
+	body := NewBody("box", []Fixture{NewBasicFixture(0, 0, w, h, MaterialSteel)})
+	body.SetType(bodyType)
+	body.Position = math.Vector{X: x, Y: y}
+	return testBody{entity: entity.Entity{ID: id}, body: body}
+}
+
+func newTestBroadphase(bodies ...testBody) *Broadphase {
+	b := NewBroadphase(broadphaseCellSize)
+	for _, tb := range bodies {
+		b.Update(tb.entity, tb.body)
+	}
+
+	return b
+}
+
+func pairOf(a, b testBody) Pair {
+	return newPair(a.entity, b.entity)
+}
+
+func TestBroadphaseSkipsStaticPairs(t *testing.T) {
+	wall1 := newTestBox(1, 0, 0, 64, 4, BodyStatic)
+	wall2 := newTestBox(2, 10, 0, 64, 4, BodyStatic)
+	door := newTestBox(3, 20, 0, 64, 4, BodyKinematic)
+	crate := newTestBox(4, 30, 0, 16, 16, BodyDynamic)
+	b := newTestBroadphase(wall1, wall2, door, crate)
+
+	expected := []Pair{pairOf(wall1, door), pairOf(wall1, crate), pairOf(wall2, door), pairOf(wall2, crate), pairOf(door, crate)}
+	if pairs := b.Pairs(); !slices.Equal(pairs, expected) {
+		t.Fatalf("unexpected pairs. want=%v have=%v", expected, pairs)
+	}
+
+	// A dynamic body without mass never moves, so it is static to the broadphase
+	crate.body.SetMassData(0, 0)
+	b.Update(crate.entity, crate.body)
+
+	expected = []Pair{pairOf(wall1, door), pairOf(wall2, door), pairOf(door, crate)}
+	if pairs := b.Pairs(); !slices.Equal(pairs, expected) {
+		t.Fatalf("unexpected pairs. want=%v have=%v", expected, pairs)
+	}
+}
+
+func TestBroadphaseRefreshesMovedBody(t *testing.T) {
+	near := newTestBox(1, 0, 0, 16, 16, BodyStatic)
+	far := newTestBox(2, 1000, 1000, 16, 16, BodyStatic)
+	mover := newTestBox(3, 10, 10, 16, 16, BodyDynamic)
+	b := newTestBroadphase(near, far, mover)
+
+	if pairs := b.Pairs(); !slices.Equal(pairs, []Pair{pairOf(near, mover)}) {
+		t.Fatalf("unexpected pairs %v", pairs)
+	}
+
+	// Moving without an update keeps the old cells until the body is refreshed
+	mover.body.Position = math.Vector{X: 1010, Y: 1010}
+	if pairs := b.Pairs(); !slices.Equal(pairs, []Pair{pairOf(near, mover)}) {
+		t.Fatalf("unexpected pairs before refresh %v", pairs)
+	}
+
+	b.Update(mover.entity, mover.body)
+	if pairs := b.Pairs(); !slices.Equal(pairs, []Pair{pairOf(far, mover)}) {
+		t.Fatalf("unexpected pairs after refresh %v", pairs)
+	}
+
+	if entities := b.Query(-20, -20, 20, 20); !slices.Equal(entities, []entity.Entity{near.entity}) {
+		t.Fatalf("expected old cells to be vacated, query returned %v", entities)
+	}
+	if entities := b.Query(990, 990, 1030, 1030); !slices.Equal(entities, []entity.Entity{far.entity, mover.entity}) {
+		t.Fatalf("expected new cells to be occupied, query returned %v", entities)
+	}
+	for key, proxies := range b.cells {
+		if len(proxies) == 0 {
+			t.Fatalf("expected empty cell %v to be deleted", key)
+		}
+	}
+}
+
+func TestBroadphaseReportsPairsOnce(t *testing.T) {
+	// Both bodies span many cells that they share
+	floor := newTestBox(1, 0, 0, 4*broadphaseCellSize, 4*broadphaseCellSize, BodyStatic)
+	rover := newTestBox(2, 0, 0, 2*broadphaseCellSize, 2*broadphaseCellSize, BodyDynamic)
+	other := newTestBox(3, 10, 10, 2*broadphaseCellSize, 2*broadphaseCellSize, BodyDynamic)
+	b := newTestBroadphase(floor, rover, other)
+
+	expected := []Pair{pairOf(floor, rover), pairOf(floor, other), pairOf(rover, other)}
+	if pairs := b.Pairs(); !slices.Equal(pairs, expected) {
+		t.Fatalf("unexpected pairs. want=%v have=%v", expected, pairs)
+	}
+}
+
+func TestBroadphaseRemove(t *testing.T) {
+	wall := newTestBox(1, 0, 0, 64, 4, BodyStatic)
+	crate := newTestBox(2, 0, 0, 16, 16, BodyDynamic)
+	b := newTestBroadphase(wall, crate)
+	b.Remove(crate.entity)
+
+	if pairs := b.Pairs(); len(pairs) != 0 {
+		t.Fatalf("unexpected pairs %v", pairs)
+	}
+	if entities := b.Query(-100, -100, 100, 100); !slices.Equal(entities, []entity.Entity{wall.entity}) {
+		t.Fatalf("unexpected query result %v", entities)
+	}
+}
+
+func TestBroadphaseMatchesAllPairs(t *testing.T) {
+	bodies := newTileMapBodies(20, 20, 50)
+	b := newTestBroadphase(bodies...)
+	if len(allPairs(bodies)) == 0 {
+		t.Fatalf("expected the layout to have overlapping pairs")
+	}
+
+	if have, want := overlappingPairs(b), allPairs(bodies); !slices.Equal(have, want) {
+		t.Fatalf("broadphase missed or invented pairs. want=%v have=%v", want, have)
+	}
+
+	moveBodies(bodies, b, rand.New(rand.NewSource(2)))
+	if have, want := overlappingPairs(b), allPairs(bodies); !slices.Equal(have, want) {
+		t.Fatalf("broadphase missed or invented pairs after moving. want=%v have=%v", want, have)
+	}
+}
+
+const tileSize = 64
+
+// newTileMapBodies lays out a map of the given number of tiles with walls around rooms of
+// 4x4 tiles and the given number of crates scattered across it.
+func newTileMapBodies(rows, cols, crates int) []testBody {
+	var bodies []testBody
+	add := func(x, y, w, h float32, bodyType BodyType) {
+		bodies = append(bodies, newTestBox(int64(len(bodies)), x, y, w, h, bodyType))
+	}
+
+	for i := 0; i <= rows; i += 4 {
+		for j := 0; j < cols; j++ {
+			add(float32(j*tileSize+tileSize/2), float32(i*tileSize), tileSize/2, 2, BodyStatic)
+		}
+	}
+	for j := 0; j <= cols; j += 4 {
+		for i := 0; i < rows; i++ {
+			add(float32(j*tileSize), float32(i*tileSize+tileSize/2), 2, tileSize/2, BodyStatic)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < crates; i++ {
+		add(r.Float32()*float32(cols*tileSize), r.Float32()*float32(rows*tileSize), 16, 16, BodyDynamic)
+	}
+
+	return bodies
+}
+
+func moveBodies(bodies []testBody, b *Broadphase, r *rand.Rand) {
+	for _, tb := range bodies {
+		if tb.body.moves() {
+			tb.body.Position = tb.body.Position.Add(math.Vector{X: r.Float32()*8 - 4, Y: r.Float32()*8 - 4})
+			b.Update(tb.entity, tb.body)
+		}
+	}
+}
+
+// overlappingPairs returns the candidate pairs of the broadphase whose bounds overlap, as
+// the narrowphase would test them.
+func overlappingPairs(b *Broadphase) []Pair {
+	candidates := b.Pairs() // refreshes the proxies
+
+	var pairs []Pair
+	bodies := map[entity.Entity]*Body{}
+	for e, p := range b.proxies {
+		bodies[e] = p.body
+	}
+
+	for _, pair := range candidates {
+		if boundsOverlap(bodies[pair.A], bodies[pair.B]) {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs
+}
+
+// allPairs is the loop the broadphase replaced: every pair of bodies, at least one of
+// which moves, whose bounds overlap.
+func allPairs(bodies []testBody) []Pair {
+	var pairs []Pair
+	for i := 0; i < len(bodies); i++ {
+		for j := i + 1; j < len(bodies); j++ {
+			if !bodies[i].body.moves() && !bodies[j].body.moves() {
+				continue
+			}
+
+			if boundsOverlap(bodies[i].body, bodies[j].body) {
+				pairs = append(pairs, pairOf(bodies[i], bodies[j]))
+			}
+		}
+	}
+
+	slices.SortFunc(pairs, comparePairs)
+	return pairs
+}
+
+func boundsOverlap(a, b *Body) bool {
+	x1a, y1a, x2a, y2a := a.CoverBound()
+	x1b, y1b, x2b, y2b := b.CoverBound()
+	return intersects(x1a, y1a, x2a, y2a, x1b, y1b, x2b, y2b)
+}
+
+func BenchmarkBroadphasePairs(b *testing.B) {
+	bodies := newTileMapBodies(100, 100, 400)
+	broadphase := newTestBroadphase(bodies...)
+	broadphase.Pairs()
+	r := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		moveBodies(bodies, broadphase, r)
+		overlappingPairs(broadphase)
+	}
+}
+
+func BenchmarkAllPairs(b *testing.B) {
+	bodies := newTileMapBodies(100, 100, 400)
+	broadphase := NewBroadphase(broadphaseCellSize) // only receives updates, for parity with the broadphase benchmark
+	r := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		moveBodies(bodies, broadphase, r)
+		allPairs(bodies)
+	}
+}
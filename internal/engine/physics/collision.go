@@ -1,73 +1,159 @@
 package physics
 
 import (
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
-	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
-	"github.com/efritz/lunar-fever/internal/engine/event"
 )
 
-type CollisionResolutionSystem struct {
-	eventManager            *event.Manager
-	physicsComponentManager *component.TypedManager[*PhysicsComponent, PhysicsComponentType]
-	entityCollection        *entity.Collection
+type collisionResolution struct {
+	world                          *World
+	physicsComponentManager        *component.TypedManager[*PhysicsComponent, PhysicsComponentType]
+	entityMovedEventManager        *EntityMovedEventManager
+	sensorEnteredEventManager      *SensorEnteredEventManager
+	sensorExitedEventManager       *SensorExitedEventManager
+	collisionStartedEventManager   *CollisionStartedEventManager
+	collisionPersistedEventManager *CollisionPersistedEventManager
+	collisionEndedEventManager     *CollisionEndedEventManager
+	overlaps                       *pairSet // sensor entity and the entity it overlaps
+	touching                       *pairSet
 }
 
-func NewCollisionResolution(eventManager *event.Manager, componentManager *component.Manager) system.System {
-	physicsComponentManager := component.NewTypedManager[*PhysicsComponent](componentManager, eventManager)
-	physicsComponentMatcher := component.NewEntityMatcher(componentManager, physicsComponentType)
-	collection := entity.NewCollection(physicsComponentMatcher, eventManager)
+// Collision describes the contact between two entities during a step. The normal points
+// from A to B, and Impulse is the total normal impulse applied to push them apart.
+type Collision struct {
+	A, B    entity.Entity
+	Points  []math.Vector
+	Normal  math.Vector
+	Impulse float32
+}
 
-	return &CollisionResolutionSystem{
-		eventManager:            eventManager,
-		physicsComponentManager: physicsComponentManager,
-		entityCollection:        collection,
+func newCollisionResolution(world *World) *collisionResolution {
+	return &collisionResolution{
+		world:                          world,
+		physicsComponentManager:        world.physicsComponentManager,
+		entityMovedEventManager:        NewEntityMovedEventManager(world.eventManager),
+		sensorEnteredEventManager:      NewSensorEnteredEventManager(world.eventManager),
+		sensorExitedEventManager:       NewSensorExitedEventManager(world.eventManager),
+		collisionStartedEventManager:   NewCollisionStartedEventManager(world.eventManager),
+		collisionPersistedEventManager: NewCollisionPersistedEventManager(world.eventManager),
+		collisionEndedEventManager:     NewCollisionEndedEventManager(world.eventManager),
+		overlaps:                       newPairSet(),
+		touching:                       newPairSet(),
 	}
 }
 
-func (d *CollisionResolutionSystem) Init() {}
-func (d *CollisionResolutionSystem) Exit() {}
+// step detects contacts between the bodies of the world and resolves them along with the
+// joints of the world.
+func (d *collisionResolution) step(elapsedMs float32) {
+	var contacts []*Contact
+	var manifolds []manifold
+	islands := newIslands()
+	placements := map[entity.Entity]placement{}
+	var touched []entity.Entity
+	overlaps := newPairSet()
+	touching := newPairSet()
 
-const iterations = 10 // TODO - rename
+	for _, pair := range d.world.pairs() {
+		component1, ok1 := d.physicsComponentManager.GetComponent(pair.A)
+		component2, ok2 := d.physicsComponentManager.GetComponent(pair.B)
+		if !ok1 || !ok2 {
+			continue
+		}
 
-func (d *CollisionResolutionSystem) Process(elapsedMs int64) {
-	var contacts []*Contact
+		if component1.CollisionsDisabled || component2.CollisionsDisabled || d.world.jointed(pair) {
+			continue
+		}
 
-	entities := d.entityCollection.Entities()
-	for i := 0; i < len(entities); i++ {
-		for j := i + 1; j < len(entities); j++ {
-			component1, _ := d.physicsComponentManager.GetComponent(entities[i])
-			component2, _ := d.physicsComponentManager.GetComponent(entities[j])
+		body1 := component1.Body
+		body2 := component2.Body
 
-			if component1.CollisionsDisabled || component2.CollisionsDisabled {
-				continue
+		// Resting bodies only need to be tested against something that moves, and anything
+		// they were touching last step they still touch
+		if !body1.simulated() && !body2.simulated() {
+			for _, p := range []Pair{pair, {pair.B, pair.A}} {
+				if d.overlaps.contains(p) {
+					overlaps.add(p)
+				}
 			}
+			if d.touching.contains(pair) {
+				touching.add(pair)
+			}
+			continue
+		}
 
-			body1 := component1.Body
-			body2 := component2.Body
+		x1a, y1a, x2a, y2a := body1.CoverBound()
+		x1b, y1b, x2b, y2b := body2.CoverBound()
 
-			if body1.inverseMass == 0 && body2.inverseMass == 0 {
-				continue
-			}
+		if !intersects(x1a, y1a, x2a, y2a, x1b, y1b, x2b, y2b) {
+			continue
+		}
 
-			x1a, y1a, x2a, y2a := body1.CoverBound()
-			x1b, y1b, x2b, y2b := body2.CoverBound()
+		n := len(contacts)
+		for _, fixture1 := range body1.Fixtures {
+			for _, fixture2 := range body2.Fixtures {
+				if !fixture1.collidesWith(fixture2) {
+					continue
+				}
 
-			if !intersects(x1a, y1a, x2a, y2a, x1b, y1b, x2b, y2b) {
-				continue
-			}
+				contact := NewContact(fixture1, body1, fixture2, body2)
+				if contact == nil {
+					continue
+				}
 
-			for _, fixture1 := range body1.Fixtures {
-				for _, fixture2 := range body2.Fixtures {
-					if contact := NewContact(fixture1, body1, fixture2, body2); contact != nil {
-						contacts = append(contacts, contact)
-					}
+				if fixture1.Sensor {
+					overlaps.add(pair)
+				} else if fixture2.Sensor {
+					overlaps.add(Pair{pair.B, pair.A})
+				} else if body1.inverseMass != 0 || body2.inverseMass != 0 {
+					// Neither of a kinematic body and a static one can give way
+					contacts = append(contacts, contact)
 				}
 			}
 		}
+		if len(contacts) == n {
+			continue
+		}
+
+		touching.add(pair)
+		manifolds = append(manifolds, manifold{pair, n, len(contacts)})
+
+		for _, e := range []entity.Entity{pair.A, pair.B} {
+			if _, ok := placements[e]; !ok {
+				component, _ := d.physicsComponentManager.GetComponent(e)
+				placements[e] = placementOf(component.Body)
+				touched = append(touched, e)
+			}
+		}
+
+		if body1.inverseMass != 0 && body2.inverseMass != 0 {
+			islands.union(body1, body2)
+		}
 	}
 
-	for j := 0; j < iterations; j++ {
+	d.physicsComponentManager.Each(func(_ entity.Entity, component *PhysicsComponent) {
+		if component.Body.inverseMass != 0 {
+			islands.add(component.Body)
+		}
+	})
+
+	joints := d.prepareJoints(islands, elapsedMs)
+
+	groups := islands.members()
+	wakeIslands(groups)
+
+	// Joints between resting bodies are already satisfied
+	joints = slices.DeleteFunc(joints, func(joint preparedJoint) bool {
+		return !joint.bodyA.simulated() && !joint.bodyB.simulated()
+	})
+
+	for j := 0; j < d.world.iterations; j++ {
+		for _, joint := range joints {
+			joint.joint.solve(joint.bodyA, joint.bodyB)
+		}
+
 		for _, contact := range contacts {
 			contact.ApplyImpulse()
 		}
@@ -76,6 +162,121 @@ func (d *CollisionResolutionSystem) Process(elapsedMs int64) {
 	for _, contact := range contacts {
 		contact.Correct()
 	}
+
+	sleepIslands(groups)
+
+	// Positional correction moves bodies outside of integration
+	for _, e := range touched {
+		if component, ok := d.physicsComponentManager.GetComponent(e); ok && placementOf(component.Body) != placements[e] {
+			d.entityMovedEventManager.Dispatch(EntityMovedEvent{e})
+		}
+	}
+
+	d.updateTouching(touching, manifolds, contacts)
+	d.updateOverlaps(overlaps)
+}
+
+type preparedJoint struct {
+	joint        Joint
+	bodyA, bodyB *Body
+}
+
+// prepareJoints readies the joints whose bodies both exist for solving, joining their
+// bodies into the same island so that they sleep and wake together.
+func (d *collisionResolution) prepareJoints(islands *islands, elapsedMs float32) []preparedJoint {
+	var joints []preparedJoint
+	for _, joint := range d.world.jointsSnapshot() {
+		a, b := joint.Entities()
+		componentA, okA := d.physicsComponentManager.GetComponent(a)
+		componentB, okB := d.physicsComponentManager.GetComponent(b)
+		if !okA || !okB {
+			continue
+		}
+
+		bodyA, bodyB := componentA.Body, componentB.Body
+		if bodyA.inverseMass != 0 && bodyB.inverseMass != 0 {
+			islands.union(bodyA, bodyB)
+		}
+
+		joint.prepare(bodyA, bodyB, elapsedMs)
+		joints = append(joints, preparedJoint{joint, bodyA, bodyB})
+	}
+
+	return joints
+}
+
+// manifold is the range of contacts generated between the fixtures of a pair of entities.
+type manifold struct {
+	pair       Pair
+	start, end int
+}
+
+// updateTouching replaces the touching pairs of the previous step, dispatching events for
+// the collisions that began, persisted, or ended in between.
+func (d *collisionResolution) updateTouching(touching *pairSet, manifolds []manifold, contacts []*Contact) {
+	previous := d.touching
+	d.touching = touching
+
+	for _, pair := range previous.difference(touching) {
+		d.collisionEndedEventManager.Dispatch(CollisionEndedEvent{A: pair.A, B: pair.B})
+	}
+
+	collisions := make([]Collision, 0, len(manifolds))
+	for _, m := range manifolds {
+		collision := newCollision(m.pair, contacts[m.start:m.end])
+		collisions = append(collisions, collision)
+
+		if previous.contains(m.pair) {
+			d.collisionPersistedEventManager.Dispatch(CollisionPersistedEvent{collision})
+		} else {
+			d.collisionStartedEventManager.Dispatch(CollisionStartedEvent{collision})
+		}
+	}
+
+	d.world.setLastCollisions(collisions)
+}
+
+// newCollision merges the contacts between the fixtures of two entities. The normal of the
+// deepest contact stands for the whole collision.
+func newCollision(pair Pair, contacts []*Contact) Collision {
+	collision := Collision{A: pair.A, B: pair.B}
+
+	deepest := float32(-1)
+	for _, contact := range contacts {
+		collision.Points = append(collision.Points, contact.contacts...)
+		collision.Impulse += contact.impulse
+
+		if contact.penetration > deepest {
+			collision.Normal = contact.normal
+			deepest = contact.penetration
+		}
+	}
+
+	return collision
+}
+
+// updateOverlaps replaces the sensor overlaps of the previous step, dispatching events for
+// the overlaps that began or ended in between.
+func (d *collisionResolution) updateOverlaps(overlaps *pairSet) {
+	previous := d.overlaps
+	d.overlaps = overlaps
+
+	for _, pair := range previous.difference(overlaps) {
+		d.sensorExitedEventManager.Dispatch(SensorExitedEvent{Sensor: pair.A, Other: pair.B})
+	}
+
+	for _, pair := range overlaps.difference(previous) {
+		d.sensorEnteredEventManager.Dispatch(SensorEnteredEvent{Sensor: pair.A, Other: pair.B})
+	}
+}
+
+type placement struct {
+	position math.Vector
+	orient   float32
+}
+
+func placementOf(body *Body) placement {
+	return placement{body.Position, body.Orient}
 }
 
 func intersects(x1a, y1a, x2a, y2a, x1b, y1b, x2b, y2b float32) bool {
@@ -0,0 +1,23 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// CollisionEndedEvent is dispatched when two entities that were touching separate, including
+// when either entity loses its body.
+type (
+	CollisionEndedEventType    struct{}
+	CollisionEndedEvent        struct{ A, B entity.Entity }
+	CollisionEndedListener     interface{ OnCollisionEnded(e CollisionEndedEvent) }
+	CollisionEndedEventManager = event.TypedManager[CollisionEndedEvent, CollisionEndedEventType, CollisionEndedListener]
+)
+
+var (
+	collisionEndedEventType       = CollisionEndedEventType{}
+	NewCollisionEndedEventManager = event.NewTypedManager[CollisionEndedEvent, CollisionEndedEventType, CollisionEndedListener]
+)
+
+func (e CollisionEndedEvent) EventType() CollisionEndedEventType { return collisionEndedEventType }
+func (e CollisionEndedEvent) Notify(l CollisionEndedListener)    { l.OnCollisionEnded(e) }
@@ -0,0 +1,24 @@
+package physics
+
+import "github.com/efritz/lunar-fever/internal/engine/event"
+
+// CollisionPersistedEvent is dispatched on every later step in which two entities keep
+// touching, as long as one of them is awake.
+type (
+	CollisionPersistedEventType struct{}
+	CollisionPersistedEvent     struct{ Collision }
+	CollisionPersistedListener  interface {
+		OnCollisionPersisted(e CollisionPersistedEvent)
+	}
+	CollisionPersistedEventManager = event.TypedManager[CollisionPersistedEvent, CollisionPersistedEventType, CollisionPersistedListener]
+)
+
+var (
+	collisionPersistedEventType       = CollisionPersistedEventType{}
+	NewCollisionPersistedEventManager = event.NewTypedManager[CollisionPersistedEvent, CollisionPersistedEventType, CollisionPersistedListener]
+)
+
+func (e CollisionPersistedEvent) EventType() CollisionPersistedEventType {
+	return collisionPersistedEventType
+}
+func (e CollisionPersistedEvent) Notify(l CollisionPersistedListener) { l.OnCollisionPersisted(e) }
@@ -0,0 +1,21 @@
+package physics
+
+import "github.com/efritz/lunar-fever/internal/engine/event"
+
+// CollisionStartedEvent is dispatched on the first step in which two entities touch.
+type (
+	CollisionStartedEventType    struct{}
+	CollisionStartedEvent        struct{ Collision }
+	CollisionStartedListener     interface{ OnCollisionStarted(e CollisionStartedEvent) }
+	CollisionStartedEventManager = event.TypedManager[CollisionStartedEvent, CollisionStartedEventType, CollisionStartedListener]
+)
+
+var (
+	collisionStartedEventType       = CollisionStartedEventType{}
+	NewCollisionStartedEventManager = event.NewTypedManager[CollisionStartedEvent, CollisionStartedEventType, CollisionStartedListener]
+)
+
+func (e CollisionStartedEvent) EventType() CollisionStartedEventType {
+	return collisionStartedEventType
+}
+func (e CollisionStartedEvent) Notify(l CollisionStartedListener) { l.OnCollisionStarted(e) }
@@ -17,6 +17,7 @@ type Contact struct {
 	restitution     float32
 	staticFriction  float32
 	dynamicFriction float32
+	impulse         float32 // normal impulse accumulated over the solver iterations
 }
 
 type penetrationQueryResult struct {
@@ -27,6 +28,10 @@ type penetrationQueryResult struct {
 // TODO - attach body to fixtures
 
 func NewContact(fixture1 Fixture, body1 *Body, fixture2 Fixture, body2 *Body) *Contact {
+	if fixture1.Shape() != ShapePolygon || fixture2.Shape() != ShapePolygon {
+		return newRoundedContact(fixture1, body1, fixture2, body2)
+	}
+
 	penetration1 := queryFaceDirections(fixture1, body1, fixture2, body2)
 	if penetration1.distance >= 0 {
 		return nil
@@ -94,32 +99,30 @@ func newContact(ref Fixture, refBody *Body, inc Fixture, incBody *Body, refIndex
 		return nil
 	}
 
+	penetration /= float32(len(contacts))
+
 	if !flipped {
-		return &Contact{
-			fixture1:        ref,
-			body1:           refBody,
-			fixture2:        inc,
-			body2:           incBody,
-			contacts:        contacts,
-			penetration:     penetration / float32(len(contacts)),
-			normal:          refFaceNormal,
-			restitution:     math.Max(inc.restitution, ref.restitution),
-			staticFriction:  math.Sqrt32(inc.staticFriction * ref.staticFriction),
-			dynamicFriction: math.Sqrt32(inc.dynamicFriction * ref.dynamicFriction),
-		}
+		return newManifoldContact(ref, refBody, inc, incBody, refFaceNormal, contacts, penetration)
 	} else {
-		return &Contact{
-			fixture1:        inc,
-			body1:           incBody,
-			fixture2:        ref,
-			body2:           refBody,
-			contacts:        contacts,
-			penetration:     penetration / float32(len(contacts)),
-			normal:          refFaceNormal.Neg(),
-			restitution:     math.Max(inc.restitution, ref.restitution),
-			staticFriction:  math.Sqrt32(inc.staticFriction * ref.staticFriction),
-			dynamicFriction: math.Sqrt32(inc.dynamicFriction * ref.dynamicFriction),
-		}
+		return newManifoldContact(inc, incBody, ref, refBody, refFaceNormal.Neg(), contacts, penetration)
+	}
+}
+
+// newManifoldContact creates a contact whose normal points from body1 to body2.
+func newManifoldContact(fixture1 Fixture, body1 *Body, fixture2 Fixture, body2 *Body, normal math.Vector, contacts []math.Vector, penetration float32) *Contact {
+	staticFriction, dynamicFriction := Friction(fixture1.Material, fixture2.Material)
+
+	return &Contact{
+		fixture1:        fixture1,
+		body1:           body1,
+		fixture2:        fixture2,
+		body2:           body2,
+		contacts:        contacts,
+		penetration:     penetration,
+		normal:          normal,
+		restitution:     Restitution(fixture1.Material, fixture2.Material),
+		staticFriction:  staticFriction,
+		dynamicFriction: dynamicFriction,
 	}
 }
 
@@ -204,13 +207,13 @@ const (
 	penetrationCorrection = float32(0.8)
 )
 
-func (c Contact) ApplyImpulse() {
+func (c *Contact) ApplyImpulse() {
 	for _, contact := range c.contacts {
 		c.applyImpulse(contact)
 	}
 }
 
-func (c Contact) applyImpulse(contact math.Vector) {
+func (c *Contact) applyImpulse(contact math.Vector) {
 	r1 := contact.Sub(c.body1.Position)
 	r2 := contact.Sub(c.body2.Position)
 
@@ -226,6 +229,7 @@ func (c Contact) applyImpulse(contact math.Vector) {
 	magnitude1 := -vn * (c.restitution + 1)
 	magnitude1 /= invMassSum
 	magnitude1 /= float32(len(c.contacts))
+	c.impulse += magnitude1
 
 	c.body1.ApplyImpulse(c.normal.Muls(magnitude1), r1, true)
 	c.body2.ApplyImpulse(c.normal.Muls(magnitude1), r2, false)
@@ -0,0 +1,253 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+// Circles and capsules are both treated as a core segment (degenerate for circles) swept
+// by a radius, so a single routine covers each pairing with polygons and with each other.
+
+func newRoundedContact(fixture1 Fixture, body1 *Body, fixture2 Fixture, body2 *Body) *Contact {
+	switch {
+	case fixture1.Shape() == ShapePolygon:
+		return collidePolygonSegment(fixture1, body1, fixture2, body2, false)
+	case fixture2.Shape() == ShapePolygon:
+		return collidePolygonSegment(fixture2, body2, fixture1, body1, true)
+	default:
+		return collideSegments(fixture1, body1, fixture2, body2)
+	}
+}
+
+// collideSegments handles circle-circle, circle-capsule, and capsule-capsule pairs.
+func collideSegments(fixture1 Fixture, body1 *Body, fixture2 Fixture, body2 *Body) *Contact {
+	a1, b1 := coreSegment(fixture1, body1)
+	a2, b2 := coreSegment(fixture2, body2)
+	p1, p2 := closestPointsOnSegments(a1, b1, a2, b2)
+
+	radius := fixture1.Radius + fixture2.Radius
+	delta := p2.Sub(p1)
+	distance := delta.Len()
+	if distance >= radius {
+		return nil
+	}
+
+	normal := delta.Divs(distance)
+	if distance == 0 {
+		// Cores overlap; separate along the line between the bodies
+		normal = fallbackNormal(body1, body2)
+	}
+
+	return newManifoldContact(fixture1, body1, fixture2, body2, normal, []math.Vector{p1.Add(normal.Muls(fixture1.Radius))}, radius-distance)
+}
+
+// collidePolygonSegment handles circle-polygon and capsule-polygon pairs. The resulting
+// normal points from the polygon to the rounded fixture unless flipped.
+func collidePolygonSegment(polygon Fixture, polygonBody *Body, rounded Fixture, roundedBody *Body, flipped bool) *Contact {
+	vertices := make([]math.Vector, len(polygon.Vertices))
+	normals := make([]math.Vector, len(polygon.Vertices))
+	for i := range polygon.Vertices {
+		vertices[i] = polygon.VertexInWorldSpace(polygonBody, i)
+		normals[i] = polygon.NormalInWorldSpace(polygonBody, i)
+	}
+
+	a, b := coreSegment(rounded, roundedBody)
+	radius := rounded.Radius
+
+	var normal math.Vector
+	var contacts []math.Vector
+	var penetration float32
+
+	if !segmentIntersectsPolygon(a, b, vertices, normals) {
+		onSegment, onPolygon := closestPointsOnSegmentAndPolygon(a, b, vertices)
+		distance := onSegment.Sub(onPolygon).Len()
+		if distance >= radius || distance == 0 {
+			return nil
+		}
+
+		normal = onSegment.Sub(onPolygon).Divs(distance)
+		penetration = radius - distance
+		contacts = []math.Vector{onSegment.Sub(normal.Muls(radius))}
+
+		// A capsule lying flat against a face touches it along its whole length
+		if a != b {
+			var endpoints []math.Vector
+			for _, e := range []math.Vector{a, b} {
+				if d, direction := distanceToPolygon(e, vertices); d < radius && direction.Dot(normal) > 0.99 {
+					endpoints = append(endpoints, e.Sub(normal.Muls(radius)))
+				}
+			}
+			if len(endpoints) == 2 {
+				contacts = endpoints
+			}
+		}
+	} else {
+		// The core is inside the polygon; push out along the face of least penetration
+		bestIndex := 0
+		best := float32(-stdmath.MaxFloat32)
+		for i := range vertices {
+			separation := math.Min(normals[i].Dot(a.Sub(vertices[i])), normals[i].Dot(b.Sub(vertices[i])))
+			if separation > best {
+				bestIndex = i
+				best = separation
+			}
+		}
+
+		normal = normals[bestIndex]
+		penetration = radius - best
+
+		// Clip the core to the side planes of the face as polygon pairs clip their incident
+		// face, so that ends sticking out of the polygon do not become contacts. A core
+		// that misses the face entirely (possible past obtuse corners) is left whole.
+		refv1, refv2 := vertices[bestIndex], vertices[(bestIndex+1)%len(vertices)]
+		sideNormal1 := normal.Orthogonalize()
+		sideNormal2 := normal.Orthogonalize().Neg()
+		if ca, cb, ok := clip(sideNormal2, refv1, a, b); ok {
+			if ca, cb, ok := clip(sideNormal1, refv2, ca, cb); ok {
+				a, b = ca, cb
+			}
+		}
+
+		for _, e := range []math.Vector{a, b} {
+			if normal.Dot(e.Sub(vertices[bestIndex])) < radius {
+				contacts = append(contacts, e.Sub(normal.Muls(radius)))
+			}
+		}
+		if a == b {
+			contacts = contacts[:1]
+		}
+	}
+
+	if flipped {
+		return newManifoldContact(rounded, roundedBody, polygon, polygonBody, normal.Neg(), contacts, penetration)
+	}
+
+	return newManifoldContact(polygon, polygonBody, rounded, roundedBody, normal, contacts, penetration)
+}
+
+func coreSegment(fixture Fixture, body *Body) (math.Vector, math.Vector) {
+	return fixture.VertexInWorldSpace(body, 0), fixture.VertexInWorldSpace(body, len(fixture.Vertices)-1)
+}
+
+func fallbackNormal(body1, body2 *Body) math.Vector {
+	if delta := body2.Position.Sub(body1.Position); delta.Len() > 0 {
+		return delta.Normalize()
+	}
+
+	return math.Vector{X: 0, Y: 1}
+}
+
+func closestPointOnSegment(p, a, b math.Vector) math.Vector {
+	ab := b.Sub(a)
+	lengthSquared := ab.Dot(ab)
+	if lengthSquared == 0 {
+		return a
+	}
+
+	t, _ := math.Clamp(p.Sub(a).Dot(ab)/lengthSquared, 0, 1)
+	return a.Add(ab.Muls(t))
+}
+
+// closestPointsOnSegments returns the closest pair of points between two segments. For
+// crossing segments both points are the intersection.
+func closestPointsOnSegments(a1, b1, a2, b2 math.Vector) (math.Vector, math.Vector) {
+	if p, ok := segmentIntersection(a1, b1, a2, b2); ok {
+		return p, p
+	}
+
+	candidates := [][2]math.Vector{
+		{a1, closestPointOnSegment(a1, a2, b2)},
+		{b1, closestPointOnSegment(b1, a2, b2)},
+		{closestPointOnSegment(a2, a1, b1), a2},
+		{closestPointOnSegment(b2, a1, b1), b2},
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate[1].Sub(candidate[0]).Len() < best[1].Sub(best[0]).Len() {
+			best = candidate
+		}
+	}
+
+	return best[0], best[1]
+}
+
+// closestPointsOnSegmentAndPolygon assumes the segment does not intersect the polygon.
+func closestPointsOnSegmentAndPolygon(a, b math.Vector, vertices []math.Vector) (onSegment, onPolygon math.Vector) {
+	best := float32(stdmath.MaxFloat32)
+	for i, v1 := range vertices {
+		v2 := vertices[(i+1)%len(vertices)]
+		p1, p2 := closestPointsOnSegments(a, b, v1, v2)
+
+		if distance := p2.Sub(p1).Len(); distance < best {
+			best = distance
+			onSegment, onPolygon = p1, p2
+		}
+	}
+
+	return onSegment, onPolygon
+}
+
+// distanceToPolygon returns the distance from a point outside the polygon to its boundary
+// along with the unit direction from the boundary to the point.
+func distanceToPolygon(p math.Vector, vertices []math.Vector) (float32, math.Vector) {
+	best := float32(stdmath.MaxFloat32)
+	var direction math.Vector
+
+	for i, v1 := range vertices {
+		q := closestPointOnSegment(p, v1, vertices[(i+1)%len(vertices)])
+		if distance := p.Sub(q).Len(); distance < best {
+			best = distance
+			direction = p.Sub(q)
+		}
+	}
+
+	if best > 0 {
+		direction = direction.Divs(best)
+	}
+
+	return best, direction
+}
+
+func segmentIntersectsPolygon(a, b math.Vector, vertices, normals []math.Vector) bool {
+	if pointInPolygon(a, vertices, normals) || pointInPolygon(b, vertices, normals) {
+		return true
+	}
+
+	for i, v1 := range vertices {
+		if _, ok := segmentIntersection(a, b, v1, vertices[(i+1)%len(vertices)]); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pointInPolygon(p math.Vector, vertices, normals []math.Vector) bool {
+	for i := range vertices {
+		if normals[i].Dot(p.Sub(vertices[i])) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func segmentIntersection(a1, b1, a2, b2 math.Vector) (math.Vector, bool) {
+	d1 := b1.Sub(a1)
+	d2 := b2.Sub(a2)
+
+	denominator := d1.Cross(d2)
+	if denominator == 0 {
+		return math.Vector{}, false
+	}
+
+	t := a2.Sub(a1).Cross(d2) / denominator
+	u := a2.Sub(a1).Cross(d1) / denominator
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return math.Vector{}, false
+	}
+
+	return a1.Add(d1.Muls(t)), true
+}
@@ -0,0 +1,98 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+func newTestBody(fixture Fixture, x, y float32) *Body {
+	body := NewBody("test", []Fixture{fixture})
+	body.Position = math.Vector{X: x, Y: y}
+	return body
+}
+
+func TestRoundedContactsWithPolygons(t *testing.T) {
+	for _, testCase := range []struct {
+		name        string
+		rounded     *Body
+		normal      math.Vector // from the box to the rounded body
+		penetration float32
+		contacts    []math.Vector
+	}{
+		{
+			name:        "circle against face",
+			rounded:     newTestBody(NewCircleFixture(0, 0, 0.5, MaterialSteel), 0, 1.25),
+			normal:      math.Vector{X: 0, Y: 1},
+			penetration: 0.25,
+			contacts:    []math.Vector{{X: 0, Y: 0.75}},
+		},
+		{
+			name:        "circle center inside",
+			rounded:     newTestBody(NewCircleFixture(0, 0, 0.5, MaterialSteel), 0, 0.75),
+			normal:      math.Vector{X: 0, Y: 1},
+			penetration: 0.75,
+			contacts:    []math.Vector{{X: 0, Y: 0.25}},
+		},
+		{
+			name:        "capsule flat against face",
+			rounded:     newTestBody(NewCapsuleFixture(-0.5, 0, 0.5, 0, 0.5, MaterialSteel), 0, 1.25),
+			normal:      math.Vector{X: 0, Y: 1},
+			penetration: 0.25,
+			contacts:    []math.Vector{{X: -0.5, Y: 0.75}, {X: 0.5, Y: 0.75}},
+		},
+		{
+			// The core runs from (-5, 0) to (1.5, 0), through the box and out both sides
+			name:        "capsule core crossing",
+			rounded:     newTestBody(NewCapsuleFixture(-3.25, 0, 3.25, 0, 0.5, MaterialSteel), -1.75, 0),
+			normal:      math.Vector{X: 0, Y: -1},
+			penetration: 1.5,
+			contacts:    []math.Vector{{X: -1, Y: 0.5}, {X: 1, Y: 0.5}},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			box := newTestBody(NewBasicFixture(0, 0, 1, 1, MaterialSteel), 0, 0)
+			box.SetType(BodyStatic)
+
+			for _, flipped := range []bool{false, true} {
+				var contact *Contact
+				if flipped {
+					contact = NewContact(testCase.rounded.Fixtures[0], testCase.rounded, box.Fixtures[0], box)
+				} else {
+					contact = NewContact(box.Fixtures[0], box, testCase.rounded.Fixtures[0], testCase.rounded)
+				}
+				if contact == nil {
+					t.Fatalf("expected a contact (flipped=%v)", flipped)
+				}
+
+				normal := testCase.normal
+				if flipped {
+					normal = normal.Neg()
+				}
+				assertNear(t, "normal", contact.normal, normal)
+				if d := contact.penetration - testCase.penetration; d > 0.01 || d < -0.01 {
+					t.Fatalf("unexpected penetration. want=%v have=%v", testCase.penetration, contact.penetration)
+				}
+
+				if len(contact.contacts) != len(testCase.contacts) {
+					t.Fatalf("unexpected contacts. want=%v have=%v", testCase.contacts, contact.contacts)
+				}
+				for i, point := range contact.contacts {
+					assertNear(t, "contact point", point, testCase.contacts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRoundedContactsMissPolygons(t *testing.T) {
+	box := newTestBody(NewBasicFixture(0, 0, 1, 1, MaterialSteel), 0, 0)
+	for _, rounded := range []*Body{
+		newTestBody(NewCircleFixture(0, 0, 0.5, MaterialSteel), 0, 1.75),
+		newTestBody(NewCapsuleFixture(-0.5, 0, 0.5, 0, 0.5, MaterialSteel), 2, 2),
+	} {
+		if contact := NewContact(box.Fixtures[0], box, rounded.Fixtures[0], rounded); contact != nil {
+			t.Fatalf("expected no contact, have %v", contact.contacts)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// DebugFrame describes the state of a world as plain primitives in world space, for tools
+// that draw it (see WriteSVG) or tests that inspect it. It holds no references into the
+// world and can be kept after the world moves on.
+type DebugFrame struct {
+	Shapes   []DebugShape
+	Bounds   []DebugBounds
+	Contacts []DebugContact
+	Joints   []DebugJoint
+}
+
+// DebugShape is a fixture placed in world space. Polygons list their vertices; circles and
+// capsules list the vertices of their core rounded by Radius.
+type DebugShape struct {
+	Entity   entity.Entity
+	Shape    Shape
+	Vertices []math.Vector
+	Radius   float32
+	Type     BodyType
+	Sensor   bool
+	Sleeping bool
+}
+
+// DebugBounds is the cover bound of a body.
+type DebugBounds struct {
+	Entity         entity.Entity
+	X1, Y1, X2, Y2 float32
+}
+
+// DebugContact is a point at which two bodies touched during the last step. The normal
+// points from A to B.
+type DebugContact struct {
+	A, B   entity.Entity
+	Point  math.Vector
+	Normal math.Vector
+}
+
+// DebugJoint is the line between the world anchors of a joint.
+type DebugJoint struct {
+	A, B             entity.Entity
+	AnchorA, AnchorB math.Vector
+}
+
+// DebugDraw captures the bodies of the world along with the contacts solved in the last
+// step and the joints between bodies. Bodies with collisions disabled are left out.
+func (w *World) DebugDraw() DebugFrame {
+	var frame DebugFrame
+
+	w.physicsComponentManager.Each(func(e entity.Entity, component *PhysicsComponent) {
+		if component.CollisionsDisabled {
+			return
+		}
+
+		body := component.Body
+		for _, fixture := range body.Fixtures {
+			vertices := make([]math.Vector, 0, len(fixture.Vertices))
+			for i := range fixture.Vertices {
+				vertices = append(vertices, fixture.VertexInWorldSpace(body, i))
+			}
+
+			frame.Shapes = append(frame.Shapes, DebugShape{
+				Entity:   e,
+				Shape:    fixture.Shape(),
+				Vertices: vertices,
+				Radius:   fixture.Radius,
+				Type:     body.bodyType,
+				Sensor:   fixture.Sensor,
+				Sleeping: body.IsSleeping(),
+			})
+		}
+
+		x1, y1, x2, y2 := body.CoverBound()
+		frame.Bounds = append(frame.Bounds, DebugBounds{Entity: e, X1: x1, Y1: y1, X2: x2, Y2: y2})
+	})
+
+	for _, collision := range w.lastCollisions() {
+		for _, point := range collision.Points {
+			frame.Contacts = append(frame.Contacts, DebugContact{A: collision.A, B: collision.B, Point: point, Normal: collision.Normal})
+		}
+	}
+
+	for _, joint := range w.jointsSnapshot() {
+		a, b := joint.Entities()
+		componentA, okA := w.physicsComponentManager.GetComponent(a)
+		componentB, okB := w.physicsComponentManager.GetComponent(b)
+		if !okA || !okB {
+			continue
+		}
+
+		bodyA, bodyB := componentA.Body, componentB.Body
+		rA, rB := joint.bodies().anchors(bodyA, bodyB)
+		frame.Joints = append(frame.Joints, DebugJoint{A: a, B: b, AnchorA: bodyA.Position.Add(rA), AnchorB: bodyB.Position.Add(rB)})
+	}
+
+	return frame
+}
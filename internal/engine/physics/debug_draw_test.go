@@ -0,0 +1,211 @@
+package physics
+
+import (
+	"bytes"
+	"flag"
+	stdmath "math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDebugDraw(t *testing.T) {
+	eventManager := event.NewManager()
+	entityManager := entity.NewManager(eventManager)
+	componentManager := component.NewManager(entityManager, eventManager)
+	physicsComponentManager := component.NewTypedManager[*PhysicsComponent](componentManager, eventManager)
+	world := NewWorld(eventManager, componentManager)
+	stepper := NewStepper(world)
+
+	add := func(body *Body, collisionsDisabled bool) entity.Entity {
+		e := entityManager.Create()
+		physicsComponentManager.AddComponent(e, &PhysicsComponent{Body: body, CollisionsDisabled: collisionsDisabled})
+		return e
+	}
+
+	floorBody := NewBody("floor", []Fixture{NewBasicFixture(0, 0, 64, 8, MaterialSteel)})
+	floorBody.SetType(BodyStatic)
+	floor := add(floorBody, false)
+
+	// Sunk 2px into the floor
+	boxBody := NewBody("box", []Fixture{NewBasicFixture(0, 0, 8, 8, MaterialSteel)})
+	boxBody.Position = math.Vector{X: 0, Y: -14}
+	box := add(boxBody, false)
+
+	wheelBody := NewBody("wheel", []Fixture{NewCircleFixture(0, 0, 8, MaterialSteel)})
+	wheelBody.Position = math.Vector{X: 100, Y: -100}
+	wheel := add(wheelBody, false)
+
+	armBody := NewBody("arm", []Fixture{NewCapsuleFixture(-8, 0, 8, 0, 4, MaterialSteel)})
+	armBody.Position = math.Vector{X: 100, Y: -120}
+	arm := add(armBody, false)
+	world.AddJoint(NewRevoluteJoint(wheel, arm, math.Vector{X: 0, Y: -10}, math.Vector{X: 0, Y: 10}))
+
+	ghostBody := NewBody("ghost", []Fixture{NewBasicFixture(0, 0, 8, 8, MaterialSteel)})
+	ghostBody.Position = math.Vector{X: 0, Y: -14}
+	ghost := add(ghostBody, true)
+
+	stepper.Process(10) // a single step
+	frame := world.DebugDraw()
+
+	t.Run("shapes", func(t *testing.T) {
+		shapes := map[entity.Entity]DebugShape{}
+		for _, shape := range frame.Shapes {
+			shapes[shape.Entity] = shape
+		}
+		if len(frame.Shapes) != 4 || len(shapes) != 4 {
+			t.Fatalf("expected one shape for each of 4 bodies, have %v", frame.Shapes)
+		}
+		if _, ok := shapes[ghost]; ok {
+			t.Fatalf("expected body with collisions disabled to be left out")
+		}
+
+		for e, expected := range map[entity.Entity]struct {
+			shape    Shape
+			bodyType BodyType
+			radius   float32
+		}{
+			floor: {ShapePolygon, BodyStatic, 0},
+			box:   {ShapePolygon, BodyDynamic, 0},
+			wheel: {ShapeCircle, BodyDynamic, 8},
+			arm:   {ShapeCapsule, BodyDynamic, 4},
+		} {
+			shape := shapes[e]
+			if shape.Shape != expected.shape || shape.Type != expected.bodyType || shape.Radius != expected.radius {
+				t.Fatalf("unexpected shape for %v: %+v", e, shape)
+			}
+		}
+
+		floorVertices := slices.Clone(shapes[floor].Vertices)
+		slices.SortFunc(floorVertices, compareVectors)
+		expectedFloorVertices := []math.Vector{{X: -64, Y: -8}, {X: -64, Y: 8}, {X: 64, Y: -8}, {X: 64, Y: 8}}
+		if !slices.Equal(floorVertices, expectedFloorVertices) {
+			t.Fatalf("unexpected floor vertices. want=%v have=%v", expectedFloorVertices, floorVertices)
+		}
+
+		assertNear(t, "wheel center", shapes[wheel].Vertices[0], wheelBody.Position)
+		assertNear(t, "arm endpoint", shapes[arm].Vertices[0], armBody.Position.Add(math.Vector{X: -8, Y: 0}))
+		assertNear(t, "arm endpoint", shapes[arm].Vertices[1], armBody.Position.Add(math.Vector{X: 8, Y: 0}))
+	})
+
+	t.Run("contacts", func(t *testing.T) {
+		if len(frame.Contacts) == 0 {
+			t.Fatalf("expected contacts between the floor and the box")
+		}
+
+		for _, contact := range frame.Contacts {
+			if contact.A != floor || contact.B != box {
+				t.Fatalf("unexpected contact between %v and %v", contact.A, contact.B)
+			}
+
+			assertNear(t, "contact normal", contact.Normal, math.Vector{X: 0, Y: -1})
+			if p := contact.Point; p.X < -8 || p.X > 8 || p.Y < -8 || p.Y > 0 {
+				t.Fatalf("expected contact point along the top of the floor below the box, have %v", p)
+			}
+		}
+	})
+
+	t.Run("joints", func(t *testing.T) {
+		if len(frame.Joints) != 1 {
+			t.Fatalf("expected one joint, have %v", frame.Joints)
+		}
+
+		joint := frame.Joints[0]
+		if joint.A != wheel || joint.B != arm {
+			t.Fatalf("unexpected joint between %v and %v", joint.A, joint.B)
+		}
+		assertNear(t, "wheel anchor", joint.AnchorA, math.Vector{X: 100, Y: -110})
+		assertNear(t, "arm anchor", joint.AnchorB, math.Vector{X: 100, Y: -110})
+	})
+}
+
+func TestWriteSVG(t *testing.T) {
+	a, b := entity.Entity{ID: 1}, entity.Entity{ID: 2}
+	frame := DebugFrame{
+		Shapes: []DebugShape{
+			{Entity: a, Shape: ShapePolygon, Vertices: []math.Vector{{X: -64, Y: -8}, {X: 64, Y: -8}, {X: 64, Y: 8}, {X: -64, Y: 8}}, Type: BodyStatic},
+			{Entity: b, Shape: ShapeCircle, Vertices: []math.Vector{{X: 0, Y: -16}}, Radius: 8, Type: BodyDynamic},
+			{Entity: b, Shape: ShapeCapsule, Vertices: []math.Vector{{X: -8, Y: -32}, {X: 8, Y: -32}}, Radius: 4, Type: BodyDynamic, Sleeping: true},
+			{Entity: b, Shape: ShapePolygon, Vertices: []math.Vector{{X: 16, Y: -24}, {X: 32, Y: -24}, {X: 24, Y: -8}}, Type: BodyKinematic, Sensor: true},
+			{Entity: b, Shape: ShapeCapsule, Vertices: []math.Vector{{X: -24, Y: -24}, {X: -24, Y: -8}}, Radius: 2, Type: BodyDynamic, Sensor: true},
+		},
+		Bounds: []DebugBounds{
+			{Entity: a, X1: -64, Y1: -8, X2: 64, Y2: 8},
+			{Entity: b, X1: -26, Y1: -36, X2: 32, Y2: -8},
+		},
+		Contacts: []DebugContact{
+			{A: a, B: b, Point: math.Vector{X: 0, Y: -8}, Normal: math.Vector{X: 0, Y: -1}},
+		},
+		Joints: []DebugJoint{
+			{A: a, B: b, AnchorA: math.Vector{X: 0, Y: 0}, AnchorB: math.Vector{X: 0, Y: -32}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := frame.WriteSVG(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	golden := filepath.Join("testdata", "debug_frame.svg")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %s", err)
+		}
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %s", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("unexpected SVG (run with -update to accept). want:\n%s\nhave:\n%s", expected, buf.Bytes())
+	}
+}
+
+func TestWriteSVGEmptyFrame(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (DebugFrame{}).WriteSVG(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"-16 -16 32 32\">\n</svg>\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected SVG. want=%q have=%q", expected, buf.String())
+	}
+}
+
+func compareVectors(a, b math.Vector) int {
+	if a.X != b.X {
+		return cmpFloat(a.X, b.X)
+	}
+
+	return cmpFloat(a.Y, b.Y)
+}
+
+func cmpFloat(a, b float32) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func assertNear(t *testing.T, name string, have, want math.Vector) {
+	t.Helper()
+
+	const tolerance = 0.05
+	if stdmath.Abs(float64(have.X-want.X)) > tolerance || stdmath.Abs(float64(have.Y-want.Y)) > tolerance {
+		t.Fatalf("unexpected %s. want=%v have=%v", name, want, have)
+	}
+}
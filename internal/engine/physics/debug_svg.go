@@ -0,0 +1,105 @@
+package physics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	stdmath "math"
+	"strings"
+)
+
+const (
+	svgPadding      = 16 // px around the bounds of the frame
+	svgNormalLength = 8  // px
+)
+
+var svgBodyColors = map[BodyType]string{
+	BodyDynamic:   "#3080ff",
+	BodyStatic:    "#808080",
+	BodyKinematic: "#30c060",
+}
+
+// WriteSVG writes the frame as a standalone SVG document, e.g. to attach to a bug report.
+// Bodies are filled by type and drawn faded while asleep, sensors are dashed outlines,
+// contacts are red points with their normals, and joints are orange lines.
+func (f DebugFrame) WriteSVG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	p := func(format string, args ...any) { fmt.Fprintf(bw, format+"\n", args...) }
+
+	x1, y1, x2, y2 := f.bound()
+	p(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%g %g %g %g">`, x1-svgPadding, y1-svgPadding, x2-x1+2*svgPadding, y2-y1+2*svgPadding)
+
+	for _, shape := range f.Shapes {
+		switch shape.Shape {
+		case ShapeCircle:
+			c := shape.Vertices[0]
+			p(`<circle cx="%g" cy="%g" r="%g" %s/>`, c.X, c.Y, shape.Radius, svgShapeStyle(shape, "fill"))
+
+		case ShapeCapsule:
+			// A round-capped line as wide as the capsule covers the same area
+			a, b := shape.Vertices[0], shape.Vertices[1]
+			p(`<line x1="%g" y1="%g" x2="%g" y2="%g" stroke-width="%g" stroke-linecap="round" %s/>`, a.X, a.Y, b.X, b.Y, 2*shape.Radius, svgShapeStyle(shape, "stroke"))
+
+		default:
+			points := make([]string, 0, len(shape.Vertices))
+			for _, v := range shape.Vertices {
+				points = append(points, fmt.Sprintf("%g,%g", v.X, v.Y))
+			}
+			p(`<polygon points="%s" %s/>`, strings.Join(points, " "), svgShapeStyle(shape, "fill"))
+		}
+	}
+
+	for _, b := range f.Bounds {
+		p(`<rect x="%g" y="%g" width="%g" height="%g" fill="none" stroke="#ff00ff" stroke-width="0.5"/>`, b.X1, b.Y1, b.X2-b.X1, b.Y2-b.Y1)
+	}
+
+	for _, joint := range f.Joints {
+		a, b := joint.AnchorA, joint.AnchorB
+		p(`<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="#ff9900" stroke-width="1"/>`, a.X, a.Y, b.X, b.Y)
+	}
+
+	for _, contact := range f.Contacts {
+		a, b := contact.Point, contact.Point.Add(contact.Normal.Muls(svgNormalLength))
+		p(`<circle cx="%g" cy="%g" r="1.5" fill="#ff0000"/>`, a.X, a.Y)
+		p(`<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="#ff0000" stroke-width="0.5"/>`, a.X, a.Y, b.X, b.Y)
+	}
+
+	p(`</svg>`)
+	return bw.Flush()
+}
+
+// svgShapeStyle returns the attributes painting a shape with the given paint ("fill" or
+// "stroke"). Sensors are outlined, or drawn faint where the shape is itself a stroke.
+func svgShapeStyle(shape DebugShape, paint string) string {
+	color := svgBodyColors[shape.Type]
+	if shape.Sensor {
+		if paint == "stroke" {
+			return fmt.Sprintf(`stroke="%s" stroke-opacity="0.3"`, color)
+		}
+
+		return fmt.Sprintf(`fill="none" stroke="%s" stroke-dasharray="2"`, color)
+	}
+
+	opacity := 0.6
+	if shape.Sleeping {
+		opacity = 0.25
+	}
+
+	return fmt.Sprintf(`%s="%s" %s-opacity="%g"`, paint, color, paint, opacity)
+}
+
+// bound returns the union of the bounds of the frame, or an empty region at the origin.
+func (f DebugFrame) bound() (x1, y1, x2, y2 float32) {
+	if len(f.Bounds) == 0 {
+		return 0, 0, 0, 0
+	}
+
+	x1, y1 = float32(stdmath.MaxFloat32), float32(stdmath.MaxFloat32)
+	x2, y2 = -x1, -y1
+	for _, b := range f.Bounds {
+		x1, y1 = min(x1, b.X1), min(y1, b.Y1)
+		x2, y2 = max(x2, b.X2), max(y2, b.Y2)
+	}
+
+	return x1, y1, x2, y2
+}
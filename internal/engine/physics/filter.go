@@ -0,0 +1,20 @@
+package physics
+
+// Category is a set of collision category bits. Each fixture belongs to the categories in
+// its Category field and collides only with fixtures whose categories intersect its Mask.
+type Category uint16
+
+const (
+	CategoryDefault Category = 1 << 0
+	CategoryAll     Category = 0xFFFF
+)
+
+// collidesWith returns true if the two fixtures accept one another. Pairs of sensors never
+// interact, as neither would respond to the other.
+func (f Fixture) collidesWith(other Fixture) bool {
+	if f.Sensor && other.Sensor {
+		return false
+	}
+
+	return f.Category&other.Mask != 0 && other.Category&f.Mask != 0
+}
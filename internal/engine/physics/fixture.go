@@ -6,16 +6,28 @@ import (
 	"github.com/efritz/lunar-fever/internal/common/math"
 )
 
+// Fixture is a convex shape attached to a body. Circles and capsules are described by
+// their core vertices (a center or a segment) rounded by Radius; polygons have no radius.
+//
+// New fixtures belong to CategoryDefault and collide with every category. A sensor fixture
+// detects overlaps without generating a collision response; see SensorEnteredEvent.
+//
+// The density of a new fixture is that of its material. It may be changed before the
+// fixture is added to a body; fixtures without density give their body infinite mass.
 type Fixture struct {
-	Vertices        []math.Vector
-	normals         []math.Vector
-	density         float32
-	restitution     float32
-	staticFriction  float32
-	dynamicFriction float32
+	Vertices []math.Vector
+	Radius   float32
+	Category Category
+	Mask     Category
+	Sensor   bool
+	Material *Material
+	Density  float32
+	normals  []math.Vector
 }
 
-func NewBasicFixture(x, y, w, h, density, restitution, staticFriction, dynamicFriction float32) Fixture {
+// NewBasicFixture creates a box centered at (x, y) in body space with the given half
+// extents.
+func NewBasicFixture(x, y, w, h float32, material *Material) Fixture {
 	vertices := []math.Vector{
 		{X: x - w, Y: y - h},
 		{X: x + w, Y: y - h},
@@ -23,10 +35,10 @@ func NewBasicFixture(x, y, w, h, density, restitution, staticFriction, dynamicFr
 		{X: x + w, Y: y + h},
 	}
 
-	return NewFixture(vertices, density, restitution, staticFriction, dynamicFriction)
+	return NewFixture(vertices, material)
 }
 
-func NewFixture(vertices []math.Vector, density, restitution, staticFriction, dynamicFriction float32) Fixture {
+func NewFixture(vertices []math.Vector, material *Material) Fixture {
 	sortByPolarAngle(vertices)
 
 	var normals []math.Vector
@@ -36,12 +48,12 @@ func NewFixture(vertices []math.Vector, density, restitution, staticFriction, dy
 	}
 
 	return Fixture{
-		Vertices:        vertices,
-		normals:         normals,
-		density:         density,
-		restitution:     restitution,
-		staticFriction:  staticFriction,
-		dynamicFriction: dynamicFriction,
+		Vertices: vertices,
+		Category: CategoryDefault,
+		Mask:     CategoryAll,
+		Material: material,
+		Density:  material.Density,
+		normals:  normals,
 	}
 }
 
@@ -0,0 +1,143 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+// bound returns the world space bounds of the fixture on the given body.
+func (f Fixture) bound(body *Body) (x1, y1, x2, y2 float32) {
+	minX := float32(+stdmath.MaxFloat32)
+	maxX := float32(-stdmath.MaxFloat32)
+	minY := float32(+stdmath.MaxFloat32)
+	maxY := float32(-stdmath.MaxFloat32)
+
+	for i := range f.Vertices {
+		v := f.VertexInWorldSpace(body, i)
+
+		minX = math.Min(minX, v.X-f.Radius)
+		maxX = math.Max(maxX, v.X+f.Radius)
+		minY = math.Min(minY, v.Y-f.Radius)
+		maxY = math.Max(maxY, v.Y+f.Radius)
+	}
+
+	return minX, minY, maxX, maxY
+}
+
+func (f Fixture) containsPoint(body *Body, p math.Vector) bool {
+	if f.Shape() != ShapePolygon {
+		a, b := coreSegment(f, body)
+		return closestPointOnSegment(p, a, b).Sub(p).Len() <= f.Radius
+	}
+
+	vertices, normals := f.inWorldSpace(body)
+	return pointInPolygon(p, vertices, normals)
+}
+
+// raycast returns the fraction along the segment from a to b at which it enters the
+// fixture, along with the surface normal at that point.
+func (f Fixture) raycast(body *Body, a, b math.Vector) (float32, math.Vector, bool) {
+	if f.containsPoint(body, a) {
+		return 0, math.Vector{}, false
+	}
+
+	if f.Shape() == ShapePolygon {
+		vertices, normals := f.inWorldSpace(body)
+		return raycastPolygon(a, b, vertices, normals)
+	}
+
+	// A rounded fixture is the union of a circle around each end of its core segment and,
+	// for capsules, the two sides of the segment offset by the radius
+	v1, v2 := coreSegment(f, body)
+	fraction, normal, ok := raycastCircle(a, b, v1, f.Radius)
+
+	if f.Shape() == ShapeCapsule {
+		if t, n, hit := raycastCircle(a, b, v2, f.Radius); hit && (!ok || t < fraction) {
+			fraction, normal, ok = t, n, true
+		}
+
+		side := v2.Sub(v1).Normalize().Orthogonalize().Muls(f.Radius)
+		for _, offset := range []math.Vector{side, side.Neg()} {
+			if t, hit := raycastSegment(a, b, v1.Add(offset), v2.Add(offset)); hit && (!ok || t < fraction) {
+				fraction, normal, ok = t, offset.Normalize(), true
+			}
+		}
+	}
+
+	return fraction, normal, ok
+}
+
+func (f Fixture) inWorldSpace(body *Body) (vertices, normals []math.Vector) {
+	for i := range f.Vertices {
+		vertices = append(vertices, f.VertexInWorldSpace(body, i))
+		normals = append(normals, f.NormalInWorldSpace(body, i))
+	}
+
+	return vertices, normals
+}
+
+// raycastPolygon clips the segment from a to b against each face of a convex polygon.
+func raycastPolygon(a, b math.Vector, vertices, normals []math.Vector) (float32, math.Vector, bool) {
+	d := b.Sub(a)
+	lower, upper := float32(0), float32(1)
+	index := -1
+
+	for i, normal := range normals {
+		numerator := normal.Dot(vertices[i].Sub(a))
+		denominator := normal.Dot(d)
+
+		if denominator == 0 {
+			if numerator < 0 {
+				return 0, math.Vector{}, false
+			}
+			continue
+		}
+
+		if denominator < 0 && numerator < lower*denominator {
+			lower = numerator / denominator
+			index = i
+		} else if denominator > 0 && numerator < upper*denominator {
+			upper = numerator / denominator
+		}
+
+		if upper < lower {
+			return 0, math.Vector{}, false
+		}
+	}
+
+	if index < 0 {
+		return 0, math.Vector{}, false
+	}
+
+	return lower, normals[index], true
+}
+
+func raycastCircle(a, b, center math.Vector, radius float32) (float32, math.Vector, bool) {
+	d := b.Sub(a)
+	s := a.Sub(center)
+
+	dd := d.Dot(d)
+	sd := s.Dot(d)
+	discriminant := sd*sd - dd*(s.Dot(s)-radius*radius)
+	if dd == 0 || discriminant < 0 {
+		return 0, math.Vector{}, false
+	}
+
+	t := -(sd + math.Sqrt32(discriminant)) / dd
+	if t < 0 || t > 1 {
+		return 0, math.Vector{}, false
+	}
+
+	return t, s.Add(d.Muls(t)).Normalize(), true
+}
+
+func raycastSegment(a, b, v1, v2 math.Vector) (float32, bool) {
+	p, ok := segmentIntersection(a, b, v1, v2)
+	if !ok {
+		return 0, false
+	}
+
+	d := b.Sub(a)
+	return p.Sub(a).Dot(d) / d.Dot(d), true
+}
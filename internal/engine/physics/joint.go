@@ -0,0 +1,228 @@
+package physics
+
+import (
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// Joint constrains the relative motion of the bodies of two entities. Joints are added to
+// a world and solved alongside contacts on every step; they are removed along with the
+// physics component of either entity.
+//
+// Anchors are given in the body space of their own body, i.e. relative to its center of
+// mass and before rotation. Limits, motors and speeds use the units of the rest of the
+// engine: radians, pixels and milliseconds.
+type Joint interface {
+	Entities() (a, b entity.Entity)
+	bodies() *JointBodies
+
+	// prepare computes the state of the joint at the start of a step.
+	prepare(bodyA, bodyB *Body, elapsedMs float32)
+
+	// solve applies impulses to the bodies towards satisfying the joint. It is called
+	// once per solver iteration.
+	solve(bodyA, bodyB *Body)
+}
+
+// JointBodies holds the parts shared by every joint. It is embedded in each joint type.
+type JointBodies struct {
+	A, B             entity.Entity
+	AnchorA, AnchorB math.Vector
+	CollideConnected bool // whether the bodies still collide with one another
+}
+
+func (j *JointBodies) Entities() (a, b entity.Entity) {
+	return j.A, j.B
+}
+
+// jointBaumgarte is the fraction of a joint's position error fed back into its velocity
+// each step.
+const jointBaumgarte = 0.2
+
+// AddJoint starts solving the given joint on the next step.
+func (w *World) AddJoint(joint Joint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.joints = append(w.joints, joint)
+	if !collideConnected(joint) {
+		w.connected[newPair(joint.Entities())]++
+	}
+}
+
+func (w *World) RemoveJoint(joint Joint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if i := slices.Index(w.joints, joint); i >= 0 {
+		w.joints = slices.Delete(w.joints, i, i+1)
+		w.disconnect(joint)
+	}
+}
+
+// Joints returns the joints attached to the given entity.
+func (w *World) Joints(e entity.Entity) []Joint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var joints []Joint
+	for _, joint := range w.joints {
+		if a, b := joint.Entities(); a == e || b == e {
+			joints = append(joints, joint)
+		}
+	}
+
+	return joints
+}
+
+func (w *World) removeJoints(e entity.Entity) {
+	w.joints = slices.DeleteFunc(w.joints, func(joint Joint) bool {
+		if a, b := joint.Entities(); a != e && b != e {
+			return false
+		}
+
+		w.disconnect(joint)
+		return true
+	})
+}
+
+func (w *World) disconnect(joint Joint) {
+	if collideConnected(joint) {
+		return
+	}
+
+	pair := newPair(joint.Entities())
+	if w.connected[pair]--; w.connected[pair] == 0 {
+		delete(w.connected, pair)
+	}
+}
+
+// jointed returns true if the two entities are held by a joint that disables collisions
+// between them.
+func (w *World) jointed(pair Pair) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.connected[pair] > 0
+}
+
+func (w *World) jointsSnapshot() []Joint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return slices.Clone(w.joints)
+}
+
+func collideConnected(joint Joint) bool {
+	return joint.bodies().CollideConnected
+}
+
+func (j *JointBodies) bodies() *JointBodies {
+	return j
+}
+
+// anchors returns the anchors of the joint rotated into world space, relative to the
+// position of their bodies.
+func (j *JointBodies) anchors(bodyA, bodyB *Body) (rA, rB math.Vector) {
+	return bodyA.Rotation.Mul(j.AnchorA), bodyB.Rotation.Mul(j.AnchorB)
+}
+
+// separation returns the vector from the world anchor of A to that of B.
+func separation(bodyA, bodyB *Body, rA, rB math.Vector) math.Vector {
+	return bodyB.Position.Add(rB).Sub(bodyA.Position.Add(rA))
+}
+
+// solvePoint applies the impulse that makes the anchors of two bodies move together, plus
+// the given bias velocity that draws them back together.
+func solvePoint(bodyA, bodyB *Body, rA, rB, bias math.Vector) {
+	mA, mB := bodyA.inverseMass, bodyB.inverseMass
+	iA, iB := bodyA.inverseInertia, bodyB.inverseInertia
+
+	k11 := mA + mB + iA*rA.Y*rA.Y + iB*rB.Y*rB.Y
+	k12 := -iA*rA.X*rA.Y - iB*rB.X*rB.Y
+	k22 := mA + mB + iA*rA.X*rA.X + iB*rB.X*rB.X
+
+	det := k11*k22 - k12*k12
+	if det == 0 {
+		return
+	}
+
+	cdot := getRelativeVelocity(bodyA, bodyB, rA, rB).Add(bias)
+	impulse := math.Vector{
+		X: -(k22*cdot.X - k12*cdot.Y) / det,
+		Y: -(k11*cdot.Y - k12*cdot.X) / det,
+	}
+
+	bodyA.ApplyImpulse(impulse, rA, true)
+	bodyB.ApplyImpulse(impulse, rB, false)
+}
+
+// solveAngle applies the impulse that makes two bodies rotate together, plus the given
+// bias angular velocity.
+func solveAngle(bodyA, bodyB *Body, bias float32) {
+	k := bodyA.inverseInertia + bodyB.inverseInertia
+	if k == 0 {
+		return
+	}
+
+	applyAngularImpulse(bodyA, bodyB, -(bodyB.AngularVelocity-bodyA.AngularVelocity+bias)/k)
+}
+
+func applyAngularImpulse(bodyA, bodyB *Body, impulse float32) {
+	bodyA.AngularVelocity -= bodyA.inverseInertia * impulse
+	bodyB.AngularVelocity += bodyB.inverseInertia * impulse
+}
+
+// axialJacobian describes motion of B relative to A along a world space direction: the
+// direction itself and the lever arms of the two bodies about it.
+type axialJacobian struct {
+	direction math.Vector
+	armA      float32
+	armB      float32
+	mass      float32 // effective mass along the direction
+}
+
+func newAxialJacobian(bodyA, bodyB *Body, direction math.Vector, leverA, leverB math.Vector) axialJacobian {
+	armA := leverA.Cross(direction)
+	armB := leverB.Cross(direction)
+	k := bodyA.inverseMass + bodyB.inverseMass + bodyA.inverseInertia*armA*armA + bodyB.inverseInertia*armB*armB
+
+	mass := float32(0)
+	if k != 0 {
+		mass = 1 / k
+	}
+
+	return axialJacobian{direction: direction, armA: armA, armB: armB, mass: mass}
+}
+
+// velocity returns the speed at which B moves away from A along the direction.
+func (j axialJacobian) velocity(bodyA, bodyB *Body) float32 {
+	return j.direction.Dot(bodyB.LinearVelocity.Sub(bodyA.LinearVelocity)) + j.armB*bodyB.AngularVelocity - j.armA*bodyA.AngularVelocity
+}
+
+func (j axialJacobian) apply(bodyA, bodyB *Body, impulse float32) {
+	p := j.direction.Muls(impulse)
+	bodyA.LinearVelocity = bodyA.LinearVelocity.Sub(p.Muls(bodyA.inverseMass))
+	bodyA.AngularVelocity -= bodyA.inverseInertia * j.armA * impulse
+	bodyB.LinearVelocity = bodyB.LinearVelocity.Add(p.Muls(bodyB.inverseMass))
+	bodyB.AngularVelocity += bodyB.inverseInertia * j.armB * impulse
+}
+
+// limitBias returns the bias velocity of a limit with the given signed distance from its
+// bound: a limit not yet reached may be approached within the step, and a violated one is
+// pushed back gradually.
+func limitBias(distance, elapsedMs float32) float32 {
+	if distance > 0 {
+		return distance / elapsedMs
+	}
+
+	return jointBaumgarte * distance / elapsedMs
+}
+
+// clampAccumulated adds an impulse to an accumulated total kept within [lower, upper] and
+// returns the portion of the impulse that may be applied.
+func clampAccumulated(accumulated *float32, impulse, lower, upper float32) float32 {
+	previous := *accumulated
+	*accumulated, _ = math.Clamp(previous+impulse, lower, upper)
+	return *accumulated - previous
+}
@@ -0,0 +1,59 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// DistanceJoint keeps the anchors of two bodies a fixed distance apart, like a rigid rod.
+// A rope only keeps them from moving further apart than its length.
+type DistanceJoint struct {
+	JointBodies
+	Length float32
+	Rope   bool
+
+	axis        axialJacobian
+	stretch     float32 // current distance beyond the length
+	elapsedMs   float32
+	ropeImpulse float32
+}
+
+func NewDistanceJoint(a, b entity.Entity, anchorA, anchorB math.Vector, length float32) *DistanceJoint {
+	return &DistanceJoint{JointBodies: JointBodies{A: a, B: b, AnchorA: anchorA, AnchorB: anchorB}, Length: length}
+}
+
+func NewRopeJoint(a, b entity.Entity, anchorA, anchorB math.Vector, length float32) *DistanceJoint {
+	joint := NewDistanceJoint(a, b, anchorA, anchorB, length)
+	joint.Rope = true
+	return joint
+}
+
+func (j *DistanceJoint) prepare(bodyA, bodyB *Body, elapsedMs float32) {
+	rA, rB := j.anchors(bodyA, bodyB)
+	d := separation(bodyA, bodyB, rA, rB)
+
+	direction := math.Vector{X: 1, Y: 0}
+	if length := d.Len(); length > 0 {
+		direction = d.Divs(length)
+	}
+
+	j.axis = newAxialJacobian(bodyA, bodyB, direction, rA, rB)
+	j.stretch = d.Len() - j.Length
+	j.elapsedMs = elapsedMs
+	j.ropeImpulse = 0
+}
+
+func (j *DistanceJoint) solve(bodyA, bodyB *Body) {
+	cdot := j.axis.velocity(bodyA, bodyB)
+
+	if !j.Rope {
+		j.axis.apply(bodyA, bodyB, -(cdot+j.stretch*jointBaumgarte/j.elapsedMs)*j.axis.mass)
+		return
+	}
+
+	// A slack rope may close the remaining distance within the step but never pushes
+	impulse := -(cdot - limitBias(-j.stretch, j.elapsedMs)) * j.axis.mass
+	j.axis.apply(bodyA, bodyB, clampAccumulated(&j.ropeImpulse, impulse, -stdmath.MaxFloat32, 0))
+}
@@ -0,0 +1,89 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// PrismaticJoint lets B slide along an axis fixed in A without rotating relative to it.
+// The translation along the axis can be limited and driven by a motor.
+type PrismaticJoint struct {
+	JointBodies
+	Axis           math.Vector // unit direction in the body space of A
+	ReferenceAngle float32
+
+	EnableLimit      bool
+	LowerTranslation float32
+	UpperTranslation float32
+
+	EnableMotor   bool
+	MotorSpeed    float32 // px/ms
+	MaxMotorForce float32
+
+	axis          axialJacobian
+	perpendicular axialJacobian
+	translation   float32
+	offset        float32 // distance off the axis
+	angleBias     float32
+	elapsedMs     float32
+	motorImpulse  float32
+	lowerImpulse  float32
+	upperImpulse  float32
+}
+
+func NewPrismaticJoint(a, b entity.Entity, anchorA, anchorB, axis math.Vector) *PrismaticJoint {
+	return &PrismaticJoint{
+		JointBodies: JointBodies{A: a, B: b, AnchorA: anchorA, AnchorB: anchorB},
+		Axis:        axis.Normalize(),
+	}
+}
+
+// Translation returns the distance B has slid along the axis as of the last step.
+func (j *PrismaticJoint) Translation() float32 {
+	return j.translation
+}
+
+func (j *PrismaticJoint) prepare(bodyA, bodyB *Body, elapsedMs float32) {
+	rA, rB := j.anchors(bodyA, bodyB)
+	d := separation(bodyA, bodyB, rA, rB)
+	axis := bodyA.Rotation.Mul(j.Axis)
+	perpendicular := axis.Orthogonalize()
+
+	// The lever arm of A reaches to the anchor of B, since that is where the axis is
+	// measured from as the bodies separate
+	j.axis = newAxialJacobian(bodyA, bodyB, axis, d.Add(rA), rB)
+	j.perpendicular = newAxialJacobian(bodyA, bodyB, perpendicular, d.Add(rA), rB)
+	j.translation = axis.Dot(d)
+	j.offset = perpendicular.Dot(d)
+	j.angleBias = remainderAngle(bodyB.Orient-bodyA.Orient-j.ReferenceAngle) * jointBaumgarte / elapsedMs
+	j.elapsedMs = elapsedMs
+	j.motorImpulse, j.lowerImpulse, j.upperImpulse = 0, 0, 0
+}
+
+func (j *PrismaticJoint) solve(bodyA, bodyB *Body) {
+	if j.EnableMotor {
+		cdot := j.axis.velocity(bodyA, bodyB) - j.MotorSpeed
+		maxImpulse := j.MaxMotorForce * j.elapsedMs
+		j.axis.apply(bodyA, bodyB, clampAccumulated(&j.motorImpulse, -cdot*j.axis.mass, -maxImpulse, maxImpulse))
+	}
+
+	if j.EnableLimit {
+		j.solveLimit(bodyA, bodyB, +1, j.translation-j.LowerTranslation, &j.lowerImpulse)
+		j.solveLimit(bodyA, bodyB, -1, j.UpperTranslation-j.translation, &j.upperImpulse)
+	}
+
+	solveAngle(bodyA, bodyB, j.angleBias)
+
+	cdot := j.perpendicular.velocity(bodyA, bodyB)
+	j.perpendicular.apply(bodyA, bodyB, -(cdot+j.offset*jointBaumgarte/j.elapsedMs)*j.perpendicular.mass)
+}
+
+// solveLimit keeps the translation on the near side of a bound. The sign is positive for a
+// lower bound and negative for an upper bound.
+func (j *PrismaticJoint) solveLimit(bodyA, bodyB *Body, sign, distance float32, accumulated *float32) {
+	cdot := sign * j.axis.velocity(bodyA, bodyB)
+	impulse := -(cdot + limitBias(distance, j.elapsedMs)) * j.axis.mass
+	j.axis.apply(bodyA, bodyB, sign*clampAccumulated(accumulated, impulse, 0, stdmath.MaxFloat32))
+}
@@ -0,0 +1,73 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// RevoluteJoint pins the anchors of two bodies together and lets them rotate freely about
+// that point. The rotation of B relative to A can be limited and driven by a motor.
+type RevoluteJoint struct {
+	JointBodies
+	ReferenceAngle float32 // orientation of B relative to A at which the joint angle is zero
+
+	EnableLimit bool
+	LowerAngle  float32
+	UpperAngle  float32
+
+	EnableMotor    bool
+	MotorSpeed     float32 // rad/ms
+	MaxMotorTorque float32
+
+	rA, rB       math.Vector
+	pointBias    math.Vector
+	angle        float32
+	elapsedMs    float32
+	motorImpulse float32
+	lowerImpulse float32
+	upperImpulse float32
+}
+
+func NewRevoluteJoint(a, b entity.Entity, anchorA, anchorB math.Vector) *RevoluteJoint {
+	return &RevoluteJoint{JointBodies: JointBodies{A: a, B: b, AnchorA: anchorA, AnchorB: anchorB}}
+}
+
+// Angle returns the rotation of B relative to A as of the last step.
+func (j *RevoluteJoint) Angle() float32 {
+	return j.angle
+}
+
+func (j *RevoluteJoint) prepare(bodyA, bodyB *Body, elapsedMs float32) {
+	j.rA, j.rB = j.anchors(bodyA, bodyB)
+	j.pointBias = separation(bodyA, bodyB, j.rA, j.rB).Muls(jointBaumgarte / elapsedMs)
+	j.angle = remainderAngle(bodyB.Orient - bodyA.Orient - j.ReferenceAngle)
+	j.elapsedMs = elapsedMs
+	j.motorImpulse, j.lowerImpulse, j.upperImpulse = 0, 0, 0
+}
+
+func (j *RevoluteJoint) solve(bodyA, bodyB *Body) {
+	k := bodyA.inverseInertia + bodyB.inverseInertia
+
+	if j.EnableMotor && k != 0 {
+		cdot := bodyB.AngularVelocity - bodyA.AngularVelocity - j.MotorSpeed
+		maxImpulse := j.MaxMotorTorque * j.elapsedMs
+		applyAngularImpulse(bodyA, bodyB, clampAccumulated(&j.motorImpulse, -cdot/k, -maxImpulse, maxImpulse))
+	}
+
+	if j.EnableLimit && k != 0 {
+		j.solveLimit(bodyA, bodyB, k, +1, j.angle-j.LowerAngle, &j.lowerImpulse)
+		j.solveLimit(bodyA, bodyB, k, -1, j.UpperAngle-j.angle, &j.upperImpulse)
+	}
+
+	solvePoint(bodyA, bodyB, j.rA, j.rB, j.pointBias)
+}
+
+// solveLimit keeps the joint angle on the near side of a bound. The sign is positive for a
+// lower bound and negative for an upper bound.
+func (j *RevoluteJoint) solveLimit(bodyA, bodyB *Body, k, sign, distance float32, accumulated *float32) {
+	cdot := sign * (bodyB.AngularVelocity - bodyA.AngularVelocity)
+	impulse := -(cdot + limitBias(distance, j.elapsedMs)) / k
+	applyAngularImpulse(bodyA, bodyB, sign*clampAccumulated(accumulated, impulse, 0, stdmath.MaxFloat32))
+}
@@ -0,0 +1,31 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+// WeldJoint holds two bodies together at their anchors at a fixed relative orientation.
+type WeldJoint struct {
+	JointBodies
+	ReferenceAngle float32 // orientation of B relative to A to hold
+
+	rA, rB    math.Vector
+	pointBias math.Vector
+	angleBias float32
+}
+
+func NewWeldJoint(a, b entity.Entity, anchorA, anchorB math.Vector) *WeldJoint {
+	return &WeldJoint{JointBodies: JointBodies{A: a, B: b, AnchorA: anchorA, AnchorB: anchorB}}
+}
+
+func (j *WeldJoint) prepare(bodyA, bodyB *Body, elapsedMs float32) {
+	j.rA, j.rB = j.anchors(bodyA, bodyB)
+	j.pointBias = separation(bodyA, bodyB, j.rA, j.rB).Muls(jointBaumgarte / elapsedMs)
+	j.angleBias = remainderAngle(bodyB.Orient-bodyA.Orient-j.ReferenceAngle) * jointBaumgarte / elapsedMs
+}
+
+func (j *WeldJoint) solve(bodyA, bodyB *Body) {
+	solveAngle(bodyA, bodyB, j.angleBias)
+	solvePoint(bodyA, bodyB, j.rA, j.rB, j.pointBias)
+}
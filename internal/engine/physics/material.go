@@ -0,0 +1,116 @@
+package physics
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+// Material describes what a fixture is made of. Density gives the mass of a fixture per
+// unit area. Restitution and friction are combined with those of the other material when
+// two fixtures touch: the bouncier of the two wins, and friction is combined by the rule
+// registered for the pair (see SetCombineRule).
+type Material struct {
+	Name            string
+	Density         float32
+	Restitution     float32
+	StaticFriction  float32
+	DynamicFriction float32
+}
+
+var (
+	MaterialRegolith  = &Material{Name: "regolith", Density: 1.5, Restitution: 0.1, StaticFriction: 0.8, DynamicFriction: 0.6}
+	MaterialSteel     = &Material{Name: "steel", Density: 20, Restitution: 0.5, StaticFriction: 0.4, DynamicFriction: 0.3}
+	MaterialRubber    = &Material{Name: "rubber", Density: 10, Restitution: 0.8, StaticFriction: 0.9, DynamicFriction: 0.7}
+	MaterialScientist = &Material{Name: "scientist", Density: 0.3, Restitution: 0.2}
+)
+
+// CombineRule decides the friction between two materials from the friction of each.
+type CombineRule int
+
+const (
+	CombineGeometricMean CombineRule = iota
+	CombineAverage
+	CombineMin
+	CombineMax
+	CombineMultiply
+)
+
+func (r CombineRule) combine(a, b float32) float32 {
+	switch r {
+	case CombineAverage:
+		return (a + b) / 2
+	case CombineMin:
+		return min(a, b)
+	case CombineMax:
+		return max(a, b)
+	case CombineMultiply:
+		return a * b
+	default:
+		return math.Sqrt32(a * b)
+	}
+}
+
+type materialPair struct {
+	a, b *Material
+}
+
+var materials = struct {
+	sync.RWMutex
+	byName map[string]*Material
+	rules  map[materialPair]CombineRule
+}{
+	byName: map[string]*Material{
+		MaterialRegolith.Name:  MaterialRegolith,
+		MaterialSteel.Name:     MaterialSteel,
+		MaterialRubber.Name:    MaterialRubber,
+		MaterialScientist.Name: MaterialScientist,
+	},
+	rules: map[materialPair]CombineRule{},
+}
+
+// RegisterMaterial makes the given material available by name, e.g. to fixtures read from
+// JSON. The name must remain stable across versions.
+func RegisterMaterial(material *Material) {
+	materials.Lock()
+	defer materials.Unlock()
+
+	if _, ok := materials.byName[material.Name]; ok {
+		panic(fmt.Sprintf("material %q already registered", material.Name))
+	}
+
+	materials.byName[material.Name] = material
+}
+
+func LookupMaterial(name string) (*Material, bool) {
+	materials.RLock()
+	defer materials.RUnlock()
+
+	material, ok := materials.byName[name]
+	return material, ok
+}
+
+// SetCombineRule sets how friction is combined between fixtures of the given materials.
+// Pairs without a rule use CombineGeometricMean.
+func SetCombineRule(a, b *Material, rule CombineRule) {
+	materials.Lock()
+	defer materials.Unlock()
+
+	materials.rules[materialPair{a, b}] = rule
+	materials.rules[materialPair{b, a}] = rule
+}
+
+// Friction returns the static and dynamic friction between the given materials.
+func Friction(a, b *Material) (staticFriction, dynamicFriction float32) {
+	materials.RLock()
+	rule := materials.rules[materialPair{a, b}]
+	materials.RUnlock()
+
+	return rule.combine(a.StaticFriction, b.StaticFriction), rule.combine(a.DynamicFriction, b.DynamicFriction)
+}
+
+// Restitution returns the restitution between the given materials.
+func Restitution(a, b *Material) float32 {
+	return max(a.Restitution, b.Restitution)
+}
@@ -2,64 +2,50 @@ package physics
 
 import (
 	stdmath "math"
-
-	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
-	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
-	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
-	"github.com/efritz/lunar-fever/internal/engine/event"
 )
 
-type PhysicsComponentSystemDelegate struct {
-	entityMovedEventManager *EntityMovedEventManager
-	physicsComponentManager *component.TypedManager[*PhysicsComponent, PhysicsComponentType]
-}
-
-func NewPhysicsComponentSystem(eventManager *event.Manager, componentManager *component.Manager) system.System {
-	physicsComponentManager := component.NewTypedManager[*PhysicsComponent](componentManager, eventManager)
-	physicsComponentMatcher := component.NewEntityMatcher(componentManager, physicsComponentType)
-	collection := entity.NewCollection(physicsComponentMatcher, eventManager)
-
-	return entity.NewSystem(&PhysicsComponentSystemDelegate{
-		entityMovedEventManager: NewEntityMovedEventManager(eventManager),
-		physicsComponentManager: physicsComponentManager,
-	}, collection)
-}
-
-func (d *PhysicsComponentSystemDelegate) Init() {}
-func (d *PhysicsComponentSystemDelegate) Exit() {}
-
 const (
 	linearDamping  = float32(2.0)
 	angularDamping = float32(2.0)
 )
 
-func (d *PhysicsComponentSystemDelegate) Process(entity entity.Entity, elapsedMs int64) {
-	physicsComponent, ok := d.physicsComponentManager.GetComponent(entity)
-	if !ok {
-		return
+// integrate advances a dynamic or kinematic body by the given number of milliseconds and
+// returns true if the body moved. Kinematic bodies move at their velocity, unaffected by
+// forces and damping.
+func integrate(body *Body, elapsedMs float32) bool {
+	if !body.moves() {
+		return false
 	}
 
-	body := physicsComponent.Body
+	position, orient := body.Position, body.Orient
+	if body.sleep.sleeping {
+		if !body.disturbed() {
+			return false
+		}
 
-	if body.inverseMass == 0 {
-		return
+		// Compare against the resting placement so that moving a sleeping body is reported
+		position, orient = body.sleep.position, body.sleep.orient
+		body.Wake()
 	}
 
-	body.LinearVelocity = body.LinearVelocity.Add(body.force.Muls(body.inverseMass * float32(elapsedMs)))
-	body.AngularVelocity = body.AngularVelocity + (body.torque * body.inverseInertia * float32(elapsedMs))
+	dynamic := body.inverseMass != 0
+	if dynamic {
+		body.LinearVelocity = body.LinearVelocity.Add(body.force.Muls(body.inverseMass * elapsedMs))
+		body.AngularVelocity = body.AngularVelocity + (body.torque * body.inverseInertia * elapsedMs)
+	}
 
-	body.Position = body.Position.Add(body.LinearVelocity.Muls(float32(elapsedMs)))
-	body.SetOrient(body.Orient + body.AngularVelocity*float32(elapsedMs))
+	body.Position = body.Position.Add(body.LinearVelocity.Muls(elapsedMs))
+	body.SetOrient(body.Orient + body.AngularVelocity*elapsedMs)
 
-	// Time-based exponential damping
-	dt := float32(elapsedMs) / 1000.0
-	linearDecay := float32(stdmath.Exp(float64(-linearDamping * dt)))
-	angularDecay := float32(stdmath.Exp(float64(-angularDamping * dt)))
-	body.LinearVelocity = body.LinearVelocity.Muls(linearDecay)
-	body.AngularVelocity = body.AngularVelocity * angularDecay
-	// TODO - only if position or orient actually changed
-	d.entityMovedEventManager.Dispatch(EntityMovedEvent{entity})
+	if dynamic {
+		// Time-based exponential damping
+		dt := elapsedMs / 1000.0
+		linearDecay := float32(stdmath.Exp(float64(-linearDamping * dt)))
+		angularDecay := float32(stdmath.Exp(float64(-angularDamping * dt)))
+		body.LinearVelocity = body.LinearVelocity.Muls(linearDecay)
+		body.AngularVelocity = body.AngularVelocity * angularDecay
+	}
+	body.updateRestTime(elapsedMs)
 
-	// TODO - can do before dispatch?
-	body.ClearForces()
+	return body.Position != position || body.Orient != orient
 }
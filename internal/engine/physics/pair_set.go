@@ -0,0 +1,36 @@
+package physics
+
+// pairSet is a set of pairs that remembers insertion order, so that the pairs tracked from
+// one step to the next are reported in a deterministic order.
+type pairSet struct {
+	pairs []Pair
+	index map[Pair]struct{}
+}
+
+func newPairSet() *pairSet {
+	return &pairSet{index: map[Pair]struct{}{}}
+}
+
+func (s *pairSet) add(pair Pair) {
+	if _, ok := s.index[pair]; !ok {
+		s.index[pair] = struct{}{}
+		s.pairs = append(s.pairs, pair)
+	}
+}
+
+func (s *pairSet) contains(pair Pair) bool {
+	_, ok := s.index[pair]
+	return ok
+}
+
+// difference returns the pairs of s that are not in other.
+func (s *pairSet) difference(other *pairSet) []Pair {
+	var pairs []Pair
+	for _, pair := range s.pairs {
+		if !other.contains(pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,131 @@
+package physics
+
+import (
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+type queryOptions struct {
+	mask     Category
+	sensors  bool
+	excluded []entity.Entity
+}
+
+type QueryOption func(*queryOptions)
+
+// WithQueryMask restricts a query to fixtures in one of the given categories.
+func WithQueryMask(mask Category) QueryOption {
+	return func(o *queryOptions) { o.mask = mask }
+}
+
+// WithSensors includes sensor fixtures, which queries skip by default.
+func WithSensors() QueryOption {
+	return func(o *queryOptions) { o.sensors = true }
+}
+
+// Excluding skips the given entities, e.g. the entity asking the question.
+func Excluding(entities ...entity.Entity) QueryOption {
+	return func(o *queryOptions) { o.excluded = append(o.excluded, entities...) }
+}
+
+func newQueryOptions(opts []QueryOption) *queryOptions {
+	o := &queryOptions{mask: CategoryAll}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (o *queryOptions) accepts(fixture Fixture) bool {
+	return fixture.Category&o.mask != 0 && (o.sensors || !fixture.Sensor)
+}
+
+// RaycastHit describes where a ray first enters a fixture. Fraction is the distance along
+// the ray as a fraction of its length, and the normal is the outward normal of the surface.
+type RaycastHit struct {
+	Entity   entity.Entity
+	Point    math.Vector
+	Normal   math.Vector
+	Fraction float32
+}
+
+// QueryPoint returns the entities with a fixture containing the given point.
+func (w *World) QueryPoint(p math.Vector, opts ...QueryOption) []entity.Entity {
+	return w.query(p.X, p.Y, p.X, p.Y, opts, func(_ entity.Entity, fixture Fixture, body *Body) bool {
+		return fixture.containsPoint(body, p)
+	})
+}
+
+// QueryAABB returns the entities with a fixture whose bounds overlap the given bounds.
+func (w *World) QueryAABB(x1, y1, x2, y2 float32, opts ...QueryOption) []entity.Entity {
+	return w.query(x1, y1, x2, y2, opts, func(_ entity.Entity, fixture Fixture, body *Body) bool {
+		fx1, fy1, fx2, fy2 := fixture.bound(body)
+		return intersects(x1, y1, x2, y2, fx1, fy1, fx2, fy2)
+	})
+}
+
+// QueryShape returns the entities with a fixture overlapping the given fixture placed at
+// the given position and orientation.
+func (w *World) QueryShape(shape Fixture, position math.Vector, orient float32, opts ...QueryOption) []entity.Entity {
+	shapeBody := &Body{Fixtures: []Fixture{shape}, Position: position}
+	shapeBody.SetOrient(orient)
+
+	x1, y1, x2, y2 := shapeBody.CoverBound()
+	return w.query(x1, y1, x2, y2, opts, func(_ entity.Entity, fixture Fixture, body *Body) bool {
+		return NewContact(shape, shapeBody, fixture, body) != nil
+	})
+}
+
+// Raycast returns the first fixture hit by the ray from one point to another. Fixtures that
+// contain the start of the ray are not hit.
+func (w *World) Raycast(from, to math.Vector, opts ...QueryOption) (RaycastHit, bool) {
+	var best RaycastHit
+	found := false
+
+	w.query(min(from.X, to.X), min(from.Y, to.Y), max(from.X, to.X), max(from.Y, to.Y), opts, func(e entity.Entity, fixture Fixture, body *Body) bool {
+		fraction, normal, ok := fixture.raycast(body, from, to)
+		if !ok || (found && fraction >= best.Fraction) {
+			return false
+		}
+
+		best = RaycastHit{Entity: e, Point: from.Add(to.Sub(from).Muls(fraction)), Normal: normal, Fraction: fraction}
+		found = true
+		return true
+	})
+
+	return best, found
+}
+
+// query tests every fixture of the bodies near the given bounds, returning the entities for
+// which the test passed on any fixture.
+func (w *World) query(x1, y1, x2, y2 float32, opts []QueryOption, test func(e entity.Entity, fixture Fixture, body *Body) bool) []entity.Entity {
+	o := newQueryOptions(opts)
+
+	var entities []entity.Entity
+	for _, e := range w.candidates(x1, y1, x2, y2) {
+		if slices.Contains(o.excluded, e) {
+			continue
+		}
+
+		component, ok := w.physicsComponentManager.GetComponent(e)
+		if !ok || component.CollisionsDisabled {
+			continue
+		}
+
+		matched := false
+		for _, fixture := range component.Body.Fixtures {
+			if o.accepts(fixture) && test(e, fixture, component.Body) {
+				matched = true
+			}
+		}
+
+		if matched {
+			entities = append(entities, e)
+		}
+	}
+
+	return entities
+}
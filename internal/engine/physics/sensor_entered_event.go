@@ -0,0 +1,23 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// SensorEnteredEvent is dispatched when a fixture of Other begins to overlap a sensor
+// fixture of Sensor.
+type (
+	SensorEnteredEventType    struct{}
+	SensorEnteredEvent        struct{ Sensor, Other entity.Entity }
+	SensorEnteredListener     interface{ OnSensorEntered(e SensorEnteredEvent) }
+	SensorEnteredEventManager = event.TypedManager[SensorEnteredEvent, SensorEnteredEventType, SensorEnteredListener]
+)
+
+var (
+	sensorEnteredEventType       = SensorEnteredEventType{}
+	NewSensorEnteredEventManager = event.NewTypedManager[SensorEnteredEvent, SensorEnteredEventType, SensorEnteredListener]
+)
+
+func (e SensorEnteredEvent) EventType() SensorEnteredEventType { return sensorEnteredEventType }
+func (e SensorEnteredEvent) Notify(l SensorEnteredListener)    { l.OnSensorEntered(e) }
@@ -0,0 +1,23 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// SensorExitedEvent is dispatched when Other no longer overlaps any sensor fixture of
+// Sensor, including when either entity loses its body.
+type (
+	SensorExitedEventType    struct{}
+	SensorExitedEvent        struct{ Sensor, Other entity.Entity }
+	SensorExitedListener     interface{ OnSensorExited(e SensorExitedEvent) }
+	SensorExitedEventManager = event.TypedManager[SensorExitedEvent, SensorExitedEventType, SensorExitedListener]
+)
+
+var (
+	sensorExitedEventType       = SensorExitedEventType{}
+	NewSensorExitedEventManager = event.NewTypedManager[SensorExitedEvent, SensorExitedEventType, SensorExitedListener]
+)
+
+func (e SensorExitedEvent) EventType() SensorExitedEventType { return sensorExitedEventType }
+func (e SensorExitedEvent) Notify(l SensorExitedListener)    { l.OnSensorExited(e) }
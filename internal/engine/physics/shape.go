@@ -0,0 +1,101 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+type Shape int
+
+const (
+	ShapePolygon Shape = iota
+	ShapeCircle
+	ShapeCapsule
+)
+
+// NewCircleFixture creates a circle centered at (x, y) in body space.
+func NewCircleFixture(x, y, radius float32, material *Material) Fixture {
+	return Fixture{
+		Vertices: []math.Vector{{X: x, Y: y}},
+		Radius:   radius,
+		Category: CategoryDefault,
+		Mask:     CategoryAll,
+		Material: material,
+		Density:  material.Density,
+	}
+}
+
+// NewCapsuleFixture creates a capsule: the segment from (x1, y1) to (x2, y2) in body space
+// swept by a circle of the given radius.
+func NewCapsuleFixture(x1, y1, x2, y2, radius float32, material *Material) Fixture {
+	return Fixture{
+		Vertices: []math.Vector{{X: x1, Y: y1}, {X: x2, Y: y2}},
+		Radius:   radius,
+		Category: CategoryDefault,
+		Mask:     CategoryAll,
+		Material: material,
+		Density:  material.Density,
+	}
+}
+
+// Shape classifies the fixture by its vertices: polygons have at least three, circles have
+// a single center, and capsules have the two endpoints of their core segment.
+func (f Fixture) Shape() Shape {
+	switch len(f.Vertices) {
+	case 1:
+		return ShapeCircle
+	case 2:
+		return ShapeCapsule
+	default:
+		return ShapePolygon
+	}
+}
+
+// massProperties returns the area and centroid of the fixture along with its moment of
+// inertia about the body origin for unit density.
+func (f Fixture) massProperties() (area float32, centroid math.Vector, inertia float32) {
+	switch f.Shape() {
+	case ShapeCircle:
+		c := f.Vertices[0]
+		rr := f.Radius * f.Radius
+		area = stdmath.Pi * rr
+		return area, c, area * (rr/2 + c.Dot(c))
+
+	case ShapeCapsule:
+		a, b := f.Vertices[0], f.Vertices[1]
+		c := a.Add(b).Divs(2)
+		length := b.Sub(a).Len()
+		rr := f.Radius * f.Radius
+
+		// A box along the segment plus a circle split between its ends
+		boxArea := 2 * f.Radius * length
+		circleArea := stdmath.Pi * rr
+		h := length / 2
+		lc := 4 * f.Radius / (3 * stdmath.Pi)
+		boxInertia := boxArea * (4*rr + length*length) / 12
+		circleInertia := circleArea * (rr/2 + h*h + 2*h*lc)
+
+		area = boxArea + circleArea
+		return area, c, boxInertia + circleInertia + area*c.Dot(c)
+	}
+
+	for i, p1 := range f.Vertices {
+		p2 := f.Vertices[(i+1)%len(f.Vertices)]
+
+		triangleArea := p1.X*p2.Y - p2.X*p1.Y
+
+		area += triangleArea
+		centroid = centroid.Add(p1.Muls(triangleArea / 6))
+		centroid = centroid.Add(p2.Muls(triangleArea / 6))
+
+		x2 := p1.X*p1.X + p1.X*p2.X + p2.X*p2.X
+		y2 := p1.Y*p1.Y + p1.Y*p2.Y + p2.Y*p2.Y
+		inertia += (x2 + y2) * triangleArea
+	}
+
+	area /= 2
+	inertia /= 12
+	centroid = centroid.Divs(area)
+	return area, centroid, inertia
+}
@@ -0,0 +1,152 @@
+package physics
+
+import (
+	"github.com/efritz/lunar-fever/internal/common/math"
+)
+
+const (
+	linearSleepTolerance  = float32(0.01)  // px/ms
+	angularSleepTolerance = float32(0.002) // rad/ms
+	timeToSleep           = float32(500)   // ms
+)
+
+// sleepState tracks how long a dynamic body has been at rest. A sleeping body is neither
+// integrated nor solved until it is woken, either explicitly, by a contact with a moving
+// body, or because gameplay code changed its velocity, forces, or placement.
+type sleepState struct {
+	sleeping bool
+	restTime float32     // ms
+	position math.Vector // placement when put to sleep
+	orient   float32
+}
+
+func (b *Body) IsSleeping() bool {
+	return b.sleep.sleeping
+}
+
+// Wake returns a sleeping body to the simulation and resets its rest timer.
+func (b *Body) Wake() {
+	b.sleep.sleeping = false
+	b.sleep.restTime = 0
+}
+
+func (b *Body) putToSleep() {
+	b.LinearVelocity = math.Vector{}
+	b.AngularVelocity = 0
+	b.sleep = sleepState{
+		sleeping: true,
+		restTime: b.sleep.restTime,
+		position: b.Position,
+		orient:   b.Orient,
+	}
+}
+
+// disturbed reports whether a sleeping body was changed from outside of the solver since it
+// was put to sleep.
+func (b *Body) disturbed() bool {
+	return b.force != (math.Vector{}) ||
+		b.torque != 0 ||
+		b.LinearVelocity != (math.Vector{}) ||
+		b.AngularVelocity != 0 ||
+		b.Position != b.sleep.position ||
+		b.Orient != b.sleep.orient
+}
+
+// updateRestTime advances the rest timer of an awake body after integration.
+func (b *Body) updateRestTime(elapsedMs float32) {
+	if b.LinearVelocity.Len() < linearSleepTolerance && math.Abs32(b.AngularVelocity) < angularSleepTolerance {
+		b.sleep.restTime += elapsedMs
+	} else {
+		b.sleep.restTime = 0
+	}
+}
+
+// simulated reports whether the body takes part in the solver: it must be dynamic or
+// kinematic, and awake.
+func (b *Body) simulated() bool {
+	return b.moves() && !b.sleep.sleeping
+}
+
+// islands groups dynamic bodies connected through contacts. Static bodies never join an
+// island, so two piles resting on the same floor sleep and wake independently.
+type islands struct {
+	parent map[*Body]*Body
+}
+
+func newIslands() *islands {
+	return &islands{parent: map[*Body]*Body{}}
+}
+
+func (i *islands) add(b *Body) {
+	if _, ok := i.parent[b]; !ok {
+		i.parent[b] = b
+	}
+}
+
+func (i *islands) find(b *Body) *Body {
+	for i.parent[b] != b {
+		i.parent[b] = i.parent[i.parent[b]]
+		b = i.parent[b]
+	}
+
+	return b
+}
+
+func (i *islands) union(a, b *Body) {
+	i.add(a)
+	i.add(b)
+	i.parent[i.find(a)] = i.find(b)
+}
+
+// members returns the bodies of each island keyed by an arbitrary representative.
+func (i *islands) members() map[*Body][]*Body {
+	members := map[*Body][]*Body{}
+	for b := range i.parent {
+		root := i.find(b)
+		members[root] = append(members[root], b)
+	}
+
+	return members
+}
+
+// wakeIslands wakes every sleeping body in an island that contains a moving body.
+func wakeIslands(groups map[*Body][]*Body) {
+	for _, bodies := range groups {
+		moving := false
+		for _, b := range bodies {
+			if !b.sleep.sleeping && b.sleep.restTime < timeToSleep {
+				moving = true
+				break
+			}
+		}
+
+		if moving {
+			for _, b := range bodies {
+				if b.sleep.sleeping {
+					b.Wake()
+				}
+			}
+		}
+	}
+}
+
+// sleepIslands puts islands to sleep once every body in them has rested long enough.
+func sleepIslands(groups map[*Body][]*Body) {
+	for _, bodies := range groups {
+		resting := true
+		for _, b := range bodies {
+			if !b.sleep.sleeping && b.sleep.restTime < timeToSleep {
+				resting = false
+				break
+			}
+		}
+
+		if resting {
+			for _, b := range bodies {
+				if !b.sleep.sleeping {
+					b.putToSleep()
+				}
+			}
+		}
+	}
+}
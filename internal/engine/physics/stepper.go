@@ -0,0 +1,99 @@
+package physics
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+)
+
+// Stepper advances a world in steps of a fixed duration, independent of the frame rate.
+// Time left over at the end of a frame is carried into the next one, and render code can
+// use World.Interpolated to draw bodies part of the way into the step in progress. Forces
+// applied to bodies during a frame act over every step taken in it.
+type Stepper struct {
+	world                   *World
+	entityMovedEventManager *EntityMovedEventManager
+	collisionResolution     *collisionResolution
+	accumulator             float32 // ms
+}
+
+func NewStepper(world *World) system.System {
+	return &Stepper{
+		world:                   world,
+		entityMovedEventManager: NewEntityMovedEventManager(world.eventManager),
+		collisionResolution:     newCollisionResolution(world),
+	}
+}
+
+func (s *Stepper) Init() {}
+func (s *Stepper) Exit() {}
+
+func (s *Stepper) Process(elapsedMs int64) {
+	timestep := s.world.timestep
+	s.accumulator += float32(elapsedMs)
+
+	for steps := 0; s.accumulator >= timestep; steps++ {
+		if steps == s.world.maxSteps {
+			s.accumulator = float32(stdmath.Mod(float64(s.accumulator), float64(timestep)))
+			break
+		}
+
+		s.step(timestep)
+		s.accumulator -= timestep
+	}
+
+	s.world.alpha = s.accumulator / timestep
+
+	// Forces are applied anew every frame
+	s.world.physicsComponentManager.Each(func(_ entity.Entity, component *PhysicsComponent) {
+		component.Body.ClearForces()
+	})
+}
+
+func (s *Stepper) step(elapsedMs float32) {
+	var moved []entity.Entity
+	s.world.physicsComponentManager.Each(func(e entity.Entity, component *PhysicsComponent) {
+		body := component.Body
+		body.previous = placementOf(body)
+		body.stepped = true
+
+		if integrate(body, elapsedMs) {
+			moved = append(moved, e)
+		}
+	})
+
+	// Fast bodies are swept once everything has moved so that they are tested against where
+	// the step left the bodies around them
+	for _, e := range moved {
+		if component, ok := s.world.physicsComponentManager.GetComponent(e); ok && s.world.needsSweep(component.Body) {
+			s.world.sweep(e, component.Body)
+		}
+	}
+
+	for _, e := range moved {
+		s.entityMovedEventManager.Dispatch(EntityMovedEvent{e})
+	}
+
+	s.collisionResolution.step(elapsedMs)
+}
+
+// Interpolated returns a copy of the body placed between its placements before and after
+// the last step, according to how far the world is into the next one. Bodies moved since
+// that step, such as by gameplay code, are blended from where the step left them.
+func (w *World) Interpolated(body *Body) Body {
+	interpolated := *body
+	if !body.stepped {
+		return interpolated
+	}
+
+	interpolated.Position = body.previous.position.Add(body.Position.Sub(body.previous.position).Muls(w.alpha))
+	interpolated.SetOrient(body.previous.orient + remainderAngle(body.Orient-body.previous.orient)*w.alpha)
+	return interpolated
+}
+
+// remainderAngle wraps an angle into [-pi, pi] so that orientations are blended along the
+// shorter way around.
+func remainderAngle(radians float32) float32 {
+	return float32(stdmath.Remainder(float64(radians), 2*stdmath.Pi))
+}
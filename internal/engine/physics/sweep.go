@@ -0,0 +1,134 @@
+package physics
+
+import (
+	stdmath "math"
+	"slices"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+)
+
+const (
+	// sweepInterval is the farthest a swept body moves between overlap tests. It is half the
+	// thickness of the thinnest walls so that no wall fits between two tests.
+	sweepInterval = 2
+
+	// sweepBisections refines the time of impact between the last clear test and the first
+	// overlapping one.
+	sweepBisections = 8
+)
+
+// needsSweep returns true if the body may have passed through something during the last
+// step: it is a bullet, or it moved faster than the bullet speed of the world.
+func (w *World) needsSweep(body *Body) bool {
+	if body.inverseMass == 0 {
+		return false
+	}
+
+	return body.Bullet || body.LinearVelocity.Len() > w.bulletSpeed
+}
+
+// sweep tests the path of a body over the last step, from its placement before the step to
+// its placement after it. If the body overlaps something along the way that it did not
+// overlap at the start, it is moved back to the time of impact, just far enough into the
+// other body for the contact to be resolved as usual.
+//
+// Other bodies are tested where the step left them.
+func (w *World) sweep(e entity.Entity, body *Body) {
+	from, to := body.previous, placementOf(body)
+
+	distance := to.position.Sub(from.position).Len()
+	if distance <= sweepInterval {
+		return
+	}
+
+	body.place(from)
+	x1a, y1a, x2a, y2a := body.CoverBound()
+	body.place(to)
+	x1b, y1b, x2b, y2b := body.CoverBound()
+
+	var obstacles []*Body
+	for _, other := range w.candidates(min(x1a, x1b), min(y1a, y1b), max(x2a, x2b), max(y2a, y2b)) {
+		if other == e || w.jointed(newPair(e, other)) {
+			continue
+		}
+
+		component, ok := w.physicsComponentManager.GetComponent(other)
+		if !ok || component.CollisionsDisabled {
+			continue
+		}
+
+		obstacles = append(obstacles, component.Body)
+	}
+
+	at := func(t float32) placement {
+		return placement{
+			position: from.position.Add(to.position.Sub(from.position).Muls(t)),
+			orient:   from.orient + remainderAngle(to.orient-from.orient)*t,
+		}
+	}
+
+	// Anything overlapped at the start of the step is already in contact and is left to
+	// the solver
+	body.place(from)
+	obstacles = slices.DeleteFunc(obstacles, func(obstacle *Body) bool { return overlaps(body, obstacle) })
+
+	n := int(stdmath.Ceil(float64(distance / sweepInterval)))
+	for i := 1; i <= n; i++ {
+		hi := float32(i) / float32(n)
+		body.place(at(hi))
+		if !overlapsAny(body, obstacles) {
+			continue
+		}
+
+		lo := float32(i-1) / float32(n)
+		for j := 0; j < sweepBisections; j++ {
+			mid := (lo + hi) / 2
+			body.place(at(mid))
+
+			if overlapsAny(body, obstacles) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+
+		body.place(at(hi))
+		return
+	}
+
+	body.place(to)
+}
+
+// overlaps returns true if a solid fixture of one body overlaps a solid fixture of the
+// other that it collides with.
+func overlaps(body1, body2 *Body) bool {
+	for _, fixture1 := range body1.Fixtures {
+		for _, fixture2 := range body2.Fixtures {
+			if fixture1.Sensor || fixture2.Sensor || !fixture1.collidesWith(fixture2) {
+				continue
+			}
+
+			if NewContact(fixture1, body1, fixture2, body2) != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func overlapsAny(body *Body, obstacles []*Body) bool {
+	for _, obstacle := range obstacles {
+		if overlaps(body, obstacle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// place moves the body to the given placement.
+func (b *Body) place(p placement) {
+	b.Position = p.position
+	b.SetOrient(p.orient)
+}
@@ -0,0 +1,49 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+func TestSweepStopsFastBodiesAtThinWalls(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		bullet bool
+		opts   []WorldOption
+	}{
+		{name: "fast", opts: nil},
+		{name: "bullet", bullet: true, opts: []WorldOption{WithBulletSpeed(10)}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			eventManager := event.NewManager()
+			entityManager := entity.NewManager(eventManager)
+			componentManager := component.NewManager(entityManager, eventManager)
+			physicsComponentManager := component.NewTypedManager[*PhysicsComponent](componentManager, eventManager)
+			stepper := NewStepper(NewWorld(eventManager, componentManager, testCase.opts...))
+
+			// A wall 4px thick at x=110
+			wall := NewBody("wall", []Fixture{NewBasicFixture(0, 0, 2, 64, MaterialSteel)})
+			wall.SetType(BodyStatic)
+			wall.Position = math.Vector{X: 110, Y: 0}
+			physicsComponentManager.AddComponent(entityManager.Create(), &PhysicsComponent{Body: wall})
+
+			// Moves about 33px per step, so it is never within the 6px either side of the
+			// center of the wall at the end of a step
+			bullet := NewBody("bullet", []Fixture{NewBasicFixture(0, 0, 4, 4, MaterialSteel)})
+			bullet.Bullet = testCase.bullet
+			bullet.LinearVelocity = math.Vector{X: 4, Y: 0}
+			physicsComponentManager.AddComponent(entityManager.Create(), &PhysicsComponent{Body: bullet})
+
+			// Long enough to take the most steps a frame allows
+			stepper.Process(100)
+
+			if bullet.Position.X >= 110 {
+				t.Fatalf("expected body to stop on the near side of the wall, ended at %v", bullet.Position)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package physics
+
+import (
+	"sync"
+
+	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+)
+
+// World indexes the bodies of every physics component in a broadphase. It is shared by the
+// stepper, which resolves contacts between candidate pairs, and by gameplay code asking
+// spatial questions (see QueryPoint, QueryAABB, QueryShape and Raycast).
+type World struct {
+	eventManager            *event.Manager
+	physicsComponentManager *component.TypedManager[*PhysicsComponent, PhysicsComponentType]
+	mu                      sync.Mutex
+	broadphase              *Broadphase
+	joints                  []Joint
+	connected               map[Pair]int // joints that disable collisions between a pair
+	collisions              []Collision  // solved in the last step, for debug drawing
+	timestep                float32
+	maxSteps                int
+	iterations              int
+	bulletSpeed             float32
+	alpha                   float32 // progress through the next step, for interpolation
+}
+
+type WorldOption func(*World)
+
+// WithTimestep sets the duration of a single step in milliseconds. Shorter steps cost more
+// but keep fast bodies from passing through thin ones.
+func WithTimestep(ms float32) WorldOption {
+	return func(w *World) { w.timestep = ms }
+}
+
+// WithMaxSteps bounds the number of steps taken in one frame. Time beyond that is dropped
+// so that a slow frame does not make the next one slower still.
+func WithMaxSteps(steps int) WorldOption {
+	return func(w *World) { w.maxSteps = steps }
+}
+
+// WithIterations sets the number of times the contacts of a step are solved.
+func WithIterations(iterations int) WorldOption {
+	return func(w *World) { w.iterations = iterations }
+}
+
+// WithBulletSpeed sets the speed in px/ms above which bodies are swept over each step so
+// that they cannot pass through thin bodies. Bodies flagged as bullets are always swept.
+func WithBulletSpeed(speed float32) WorldOption {
+	return func(w *World) { w.bulletSpeed = speed }
+}
+
+const (
+	// broadphaseCellSize is a few tiles wide so that most bodies span a handful of cells.
+	broadphaseCellSize = 128
+
+	defaultTimestep   = float32(1000) / 120
+	defaultMaxSteps   = 8
+	defaultIterations = 10
+
+	// defaultBulletSpeed covers the rover at full speed.
+	defaultBulletSpeed = float32(0.25)
+)
+
+func NewWorld(eventManager *event.Manager, componentManager *component.Manager, opts ...WorldOption) *World {
+	w := &World{
+		eventManager:            eventManager,
+		physicsComponentManager: component.NewTypedManager[*PhysicsComponent](componentManager, eventManager),
+		broadphase:              NewBroadphase(broadphaseCellSize),
+		connected:               map[Pair]int{},
+		timestep:                defaultTimestep,
+		maxSteps:                defaultMaxSteps,
+		iterations:              defaultIterations,
+		bulletSpeed:             defaultBulletSpeed,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.physicsComponentManager.Each(func(e entity.Entity, component *PhysicsComponent) { w.update(e, component.Body) })
+	w.physicsComponentManager.OnAdded(func(e entity.Entity, component *PhysicsComponent) { w.update(e, component.Body) })
+	w.physicsComponentManager.OnRemoved(func(e entity.Entity, _ *PhysicsComponent) { w.remove(e) })
+	NewEntityMovedEventManager(eventManager).AddListener(w)
+	return w
+}
+
+func (w *World) OnEntityMoved(e EntityMovedEvent) {
+	if component, ok := w.physicsComponentManager.GetComponent(e.Entity); ok {
+		w.update(e.Entity, component.Body)
+	}
+}
+
+func (w *World) update(e entity.Entity, body *Body) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.broadphase.Update(e, body)
+}
+
+func (w *World) remove(e entity.Entity) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.broadphase.Remove(e)
+	w.removeJoints(e)
+}
+
+// pairs returns a copy of the candidate pairs of the broadphase.
+func (w *World) pairs() []Pair {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Pair(nil), w.broadphase.Pairs()...)
+}
+
+// candidates returns the entities whose cells overlap the given bounds.
+func (w *World) candidates(x1, y1, x2, y2 float32) []entity.Entity {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.broadphase.Query(x1, y1, x2, y2)
+}
+
+func (w *World) setLastCollisions(collisions []Collision) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.collisions = collisions
+}
+
+func (w *World) lastCollisions() []Collision {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.collisions
+}
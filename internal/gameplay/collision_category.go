@@ -0,0 +1,12 @@
+package gameplay
+
+import "github.com/efritz/lunar-fever/internal/engine/physics"
+
+// Collision categories of gameplay fixtures. Prefabs refer to these by value, so existing
+// bits must not be renumbered.
+const (
+	categoryScenery   = physics.CategoryDefault // walls, benches, and anything unassigned
+	categoryCharacter = physics.Category(1 << 1)
+	categoryVehicle   = physics.Category(1 << 2)
+	categoryDoor      = physics.Category(1 << 3)
+)
@@ -2,11 +2,15 @@ package gameplay
 
 import (
 	"github.com/efritz/lunar-fever/internal/engine"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/command"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/component"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/group"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity/tag"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/query"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/snapshot"
 	"github.com/efritz/lunar-fever/internal/engine/event"
+	"github.com/efritz/lunar-fever/internal/engine/hierarchy"
 	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/efritz/lunar-fever/internal/gameplay/maps"
 )
@@ -23,13 +27,19 @@ type GameContext struct {
 	ComponentManager *component.Manager
 	TagManager       *tag.Manager
 	GroupManager     *group.Manager
+	CommandBuffer    *command.Buffer
+	HierarchyManager *hierarchy.Manager
+	PhysicsWorld     *physics.World
 
 	PhysicsComponentManager     *component.TypedManager[*physics.PhysicsComponent, physics.PhysicsComponentType]
 	PathfindingComponentManager *component.TypedManager[*PathfindingComponent, PathfindingComponentType]
 	HealthComponentManager      *component.TypedManager[*HealthComponent, HealthComponentType]
 	InteractionComponentManager *component.TypedManager[*InteractionComponent, InteractionComponentType]
+	RoverPartComponentManager   *component.TypedManager[*RoverPartComponent, RoverPartComponentType]
+
+	Serializer *snapshot.Serializer
+	prefabs    map[string]snapshot.EntitySnapshot
 
-	PlayerCollection    *entity.Collection
 	ScientistCollection *entity.Collection
 	NpcCollection       *entity.Collection
 	RoverCollection     *entity.Collection
@@ -40,11 +50,13 @@ type GameContext struct {
 func NewGameContext(engineCtx *engine.Context, tileMap *maps.TileMap, base *maps.Base) *GameContext {
 	eventManager := event.NewManager()
 	entityManager := entity.NewManager(eventManager)
-	componentManager := component.NewManager(eventManager)
-	tagManager := tag.NewManager(eventManager)
-	groupManager := group.NewManager(eventManager)
+	componentManager := component.NewManager(entityManager, eventManager, component.WithStore(component.NewSparseSetStore()))
+	tagManager := tag.NewManager(entityManager, eventManager)
+	groupManager := group.NewManager(entityManager, eventManager)
+	physicsComponentManager := component.NewTypedManager[*physics.PhysicsComponent](componentManager, eventManager)
+	newQuery := func() *query.Builder { return query.NewBuilder(componentManager, tagManager, groupManager) }
 
-	return &GameContext{
+	ctx := &GameContext{
 		Context:        engineCtx,
 		TileMap:        tileMap,
 		Base:           base,
@@ -55,17 +67,37 @@ func NewGameContext(engineCtx *engine.Context, tileMap *maps.TileMap, base *maps
 		ComponentManager: componentManager,
 		TagManager:       tagManager,
 		GroupManager:     groupManager,
+		CommandBuffer:    command.NewBuffer(entityManager, tagManager, groupManager),
+		HierarchyManager: hierarchy.NewManager(entityManager, eventManager, componentManager, hierarchy.WithRootTransform(physicsRootTransform(physicsComponentManager))),
+		PhysicsWorld:     physics.NewWorld(eventManager, componentManager),
 
-		PhysicsComponentManager:     component.NewTypedManager[*physics.PhysicsComponent](componentManager, eventManager),
+		PhysicsComponentManager:     physicsComponentManager,
 		PathfindingComponentManager: component.NewTypedManager[*PathfindingComponent](componentManager, eventManager),
 		HealthComponentManager:      component.NewTypedManager[*HealthComponent](componentManager, eventManager),
 		InteractionComponentManager: component.NewTypedManager[*InteractionComponent](componentManager, eventManager),
+		RoverPartComponentManager:   component.NewTypedManager[*RoverPartComponent](componentManager, eventManager),
+
+		prefabs: map[string]snapshot.EntitySnapshot{},
 
-		PlayerCollection:    entity.NewCollection(tag.NewEntityMatcher(tagManager, "player"), eventManager),
-		ScientistCollection: entity.NewCollection(group.NewEntityMatcher(groupManager, "scientist"), eventManager),
+		ScientistCollection: newQuery().All(query.Group("scientist"), query.Component(physics.PhysicsComponentType{})).Collection(eventManager),
 		NpcCollection:       entity.NewCollection(group.NewEntityMatcher(groupManager, "npc"), eventManager),
 		RoverCollection:     entity.NewCollection(tag.NewEntityMatcher(tagManager, "rover"), eventManager),
-		DoorCollection:      entity.NewCollection(group.NewEntityMatcher(groupManager, "door"), eventManager),
-		PhysicsCollection:   entity.NewCollection(group.NewEntityMatcher(groupManager, "physics"), eventManager),
+		DoorCollection:      newQuery().All(query.Group("door"), query.Component(physics.PhysicsComponentType{})).Collection(eventManager),
+		PhysicsCollection:   entity.NewCollection(group.NewEntityMatcher(groupManager, "physics"), eventManager, entity.WithIncrementalIndex()),
+	}
+
+	ctx.Serializer = newSerializer(ctx)
+	return ctx
+}
+
+// physicsRootTransform places the root of an entity hierarchy at the center of its body.
+func physicsRootTransform(physicsComponentManager *component.TypedManager[*physics.PhysicsComponent, physics.PhysicsComponentType]) func(e entity.Entity) (hierarchy.Transform, bool) {
+	return func(e entity.Entity) (hierarchy.Transform, bool) {
+		physicsComponent, ok := physicsComponentManager.GetComponent(e)
+		if !ok {
+			return hierarchy.Transform{}, false
+		}
+
+		return hierarchy.Transform{Position: physicsComponent.Body.Position, Rotation: physicsComponent.Body.Orient}, true
 	}
 }
@@ -1,7 +1,9 @@
 package gameplay
 
 import (
+	"github.com/efritz/lunar-fever/internal/engine/ecs/query"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
 )
 
 type doorOpenerSystem struct {
@@ -16,26 +18,39 @@ func (s *doorOpenerSystem) Init() {}
 func (s *doorOpenerSystem) Exit() {}
 
 func (s *doorOpenerSystem) Process(elapsedMs int64) {
+	scientists := query.Join1(s.ScientistCollection, s.PhysicsComponentManager)
 
-	for _, entity := range s.DoorCollection.Entities() {
-		component, ok := s.PhysicsComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		collisionsDisabled := false
-		for _, entity := range s.ScientistCollection.Entities() {
-			scientistPhysicsComponent, ok := s.PhysicsComponentManager.GetComponent(entity)
-			if !ok {
-				return
-			}
-
-			if component.Body.Position.Sub(scientistPhysicsComponent.Body.Position).Len() < 50 {
-				collisionsDisabled = true
+	for _, door := range query.Join1(s.DoorCollection, s.PhysicsComponentManager) {
+		open := false
+		for _, scientist := range scientists {
+			if door.C1.Body.Position.Sub(scientist.C1.Body.Position).Len() < 50 {
+				open = true
 				break
 			}
 		}
 
-		component.CollisionsDisabled = collisionsDisabled
+		setDoorOpen(door.C1.Body, open)
+	}
+}
+
+// setDoorOpen lets characters through an open door. Vehicles are blocked either way.
+func setDoorOpen(body *physics.Body, open bool) {
+	mask := physics.CategoryAll
+	if open {
+		mask &^= categoryCharacter
 	}
+
+	for i := range body.Fixtures {
+		body.Fixtures[i].Mask = mask
+	}
+}
+
+func isDoorOpen(body *physics.Body) bool {
+	for _, fixture := range body.Fixtures {
+		if fixture.Mask&categoryCharacter == 0 {
+			return true
+		}
+	}
+
+	return false
 }
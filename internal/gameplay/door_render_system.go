@@ -27,7 +27,7 @@ func (s *doorRenderSystem) Process(elapsedMs int64) {
 
 	for _, entity := range s.DoorCollection.Entities() {
 		component, ok := s.PhysicsComponentManager.GetComponent(entity)
-		if !ok || component.CollisionsDisabled {
+		if !ok || isDoorOpen(component.Body) {
 			continue
 		}
 
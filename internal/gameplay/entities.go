@@ -1,107 +1,130 @@
 package gameplay
 
 import (
+	stdmath "math"
+
 	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/hierarchy"
 	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 	"github.com/efritz/lunar-fever/internal/gameplay/maps"
 )
 
 func createPlayer(ctx *GameContext) {
-	player := ctx.EntityManager.Create()
-	ctx.TagManager.SetTag(player, "player")
-	ctx.GroupManager.AddGroup(player, "scientist")
-	ctx.GroupManager.AddGroup(player, "physics")
-
-	body := physics.NewBody("scientist", []physics.Fixture{
-		physics.NewBasicFixture(
-			0, 0, 16, 16, // bounds
-			0.3, 0.2, // material
-			0, 0, // friction
-		),
-	})
-	body.Position = math.Vector{rendering.DisplayWidth - 200, 400}
-	ctx.PhysicsComponentManager.AddComponent(player, &physics.PhysicsComponent{Body: body})
-	ctx.InteractionComponentManager.AddComponent(player, &InteractionComponent{})
-	ctx.HealthComponentManager.AddComponent(player, &HealthComponent{Health: 100, MaxHealth: 100})
+	ctx.mustSpawnPrefab("player", math.Vector{rendering.DisplayWidth - 200, 400})
 }
 
 func createScientist(ctx *GameContext) {
-	player := ctx.EntityManager.Create()
-	ctx.GroupManager.AddGroup(player, "scientist")
-	ctx.GroupManager.AddGroup(player, "physics")
-	ctx.GroupManager.AddGroup(player, "npc")
-
-	body := physics.NewBody("scientist", []physics.Fixture{
-		physics.NewBasicFixture(
-			0, 0, 16, 16, // bounds
-			0.3, 0.2, // material
-			0, 0, // friction
-		),
-	})
-	body.Position = math.Vector{rendering.DisplayWidth - 100, 300}
-	ctx.PhysicsComponentManager.AddComponent(player, &physics.PhysicsComponent{Body: body})
-	ctx.PathfindingComponentManager.AddComponent(player, &PathfindingComponent{})
-	ctx.HealthComponentManager.AddComponent(player, &HealthComponent{Health: 100, MaxHealth: 100})
+	ctx.mustSpawnPrefab("scientist", math.Vector{rendering.DisplayWidth - 100, 300})
 }
 
 func createRover(ctx *GameContext) {
-	rover := ctx.EntityManager.Create()
-	ctx.TagManager.SetTag(rover, "rover")
-	ctx.GroupManager.AddGroup(rover, "physics")
-
-	body := physics.NewBody("rover", []physics.Fixture{
-		physics.NewBasicFixture(
-			0, 0, 69, 123, // bounds
-			20, 0.5, // material
-			0, 0, // friction
-		),
-	})
-	body.Position = math.Vector{rendering.DisplayWidth / 4, rendering.DisplayHeight / 4}
-	ctx.PhysicsComponentManager.AddComponent(rover, &physics.PhysicsComponent{Body: body})
+	ctx.mustSpawnPrefab("rover", math.Vector{rendering.DisplayWidth / 4, rendering.DisplayHeight / 4})
+}
+
+// Rover geometry in pixels, matching the textures under assets/textures/rover
+const (
+	roverAxleOffset = 60 // distance of each axle from the center of the chassis
+	roverAxleWidth  = 177
+	roverAxleHeight = 45
+	roverTireWidth  = 22
+	roverTireHeight = 45
+)
+
+// Rover steering, in the units of the physics engine
+const (
+	roverMaxSteer    = stdmath.Pi / 6 // rad either side of straight ahead
+	roverSteerTorque = 5e4
+)
+
+// attachRoverParts creates the axles and tires of a rover as children of its chassis.
+// Tires are attached at the ends of their axle and are jointed to the chassis at their
+// center; the front tires steer and the rear tires are welded in place.
+func attachRoverParts(ctx *GameContext, rover entity.Entity) {
+	physicsComponent, ok := ctx.PhysicsComponentManager.GetComponent(rover)
+	if !ok {
+		return
+	}
+	chassis := physicsComponent.Body
+
+	axles := []struct {
+		offset    float32
+		steerable bool
+	}{
+		{-roverAxleOffset, true},
+		{+roverAxleOffset, false},
+	}
+
+	for _, axle := range axles {
+		axleLocal := hierarchy.Transform{Position: math.Vector{0, axle.offset}}
+		axleEntity := ctx.EntityManager.Create()
+		ctx.HierarchyManager.Attach(axleEntity, rover, axleLocal)
+		ctx.RoverPartComponentManager.AddComponent(axleEntity, &RoverPartComponent{Part: RoverAxle})
+
+		for _, part := range []RoverPart{RoverTireLeft, RoverTireRight} {
+			x := float32(roverAxleWidth / 2)
+			if part == RoverTireLeft {
+				x = -x
+			}
+
+			tireLocal := hierarchy.Transform{Position: math.Vector{x, 0}}
+			tireEntity := ctx.EntityManager.Create()
+			ctx.HierarchyManager.Attach(tireEntity, axleEntity, tireLocal)
+			ctx.GroupManager.AddGroup(tireEntity, "physics")
+
+			// Tires extend outward from the end of their axle
+			center := axleLocal.Apply(tireLocal).Position.Add(math.Vector{x / math.Abs32(x) * roverTireWidth / 2, 0})
+
+			body := physics.NewBody("tire", []physics.Fixture{
+				physics.NewBasicFixture(0, 0, roverTireWidth/2, roverTireHeight/2, physics.MaterialRubber),
+			})
+			body.Fixtures[0].Category = categoryVehicle
+			body.Position = chassis.Position.Add(chassis.Rotation.Mul(center))
+			body.SetOrient(chassis.Orient)
+			body.LinearVelocity = chassis.LinearVelocity
+			ctx.PhysicsComponentManager.AddComponent(tireEntity, &physics.PhysicsComponent{Body: body})
+
+			roverPartComponent := &RoverPartComponent{Part: part}
+			if axle.steerable {
+				joint := physics.NewRevoluteJoint(rover, tireEntity, center, math.Vector{})
+				joint.EnableLimit, joint.LowerAngle, joint.UpperAngle = true, -roverMaxSteer, roverMaxSteer
+				joint.EnableMotor, joint.MaxMotorTorque = true, roverSteerTorque
+				ctx.PhysicsWorld.AddJoint(joint)
+				roverPartComponent.Steering = joint
+			} else {
+				ctx.PhysicsWorld.AddJoint(physics.NewWeldJoint(rover, tireEntity, center, math.Vector{}))
+			}
+
+			ctx.RoverPartComponentManager.AddComponent(tireEntity, roverPartComponent)
+		}
+	}
 }
 
 func createWalls(ctx *GameContext) {
 	type Options struct {
-		name    string
-		w       float32
-		h       float32
+		prefab  string
 		iOffset float32
 		jOffset float32
 	}
 
-	build := func(i, j int, opts Options) {
-		entity := ctx.EntityManager.Create()
-		ctx.GroupManager.AddGroup(entity, "physics")
-		ctx.GroupManager.AddGroup(entity, opts.name)
-
-		body := physics.NewBody(opts.name, []physics.Fixture{
-			physics.NewBasicFixture(
-				0, 0, opts.w, opts.h, // bounds
-				0.0, 0.5, // material
-				0, 0, // friction
-			),
-		})
-		body.Position = math.Vector{float32(j*64) + opts.jOffset, float32(i*64) + opts.iOffset}
-		ctx.PhysicsComponentManager.AddComponent(entity, &physics.PhysicsComponent{Body: body})
-	}
-
 	parametersByBit := map[maps.TileBitIndex]Options{
-		maps.INTERIOR_WALL_N_BIT: {"wall", 32, 2, +1, 32},
-		maps.INTERIOR_WALL_S_BIT: {"wall", 32, 2, 64 - 1, 32},
-		maps.INTERIOR_WALL_W_BIT: {"wall", 2, 32, 32, +1},
-		maps.INTERIOR_WALL_E_BIT: {"wall", 2, 32, 32, 64 - 1},
-		maps.DOOR_N_BIT:          {"door", 32, 2, +1, 32},
-		maps.DOOR_S_BIT:          {"door", 32, 2, 64 - 1, 32},
-		maps.DOOR_W_BIT:          {"door", 2, 32, 32, +1},
-		maps.DOOR_E_BIT:          {"door", 2, 32, 32, 64 - 1},
+		maps.INTERIOR_WALL_N_BIT: {"wall_horizontal", +1, 32},
+		maps.INTERIOR_WALL_S_BIT: {"wall_horizontal", 64 - 1, 32},
+		maps.INTERIOR_WALL_W_BIT: {"wall_vertical", 32, +1},
+		maps.INTERIOR_WALL_E_BIT: {"wall_vertical", 32, 64 - 1},
+		maps.DOOR_N_BIT:          {"door_horizontal", +1, 32},
+		maps.DOOR_S_BIT:          {"door_horizontal", 64 - 1, 32},
+		maps.DOOR_W_BIT:          {"door_vertical", 32, +1},
+		maps.DOOR_E_BIT:          {"door_vertical", 32, 64 - 1},
 	}
 
 	for i := 0; i < ctx.TileMap.Height(); i++ {
 		for j := 0; j < ctx.TileMap.Width(); j++ {
-			for bit, opts := range parametersByBit {
-				if ctx.TileMap.GetBit(i, j, bit) {
-					build(i, j, opts)
+			// Walk bits in a fixed order so entity IDs are stable between runs
+			for _, bit := range maps.TileBitIndexes {
+				if opts, ok := parametersByBit[bit]; ok && ctx.TileMap.GetBit(i, j, bit) {
+					ctx.mustSpawnPrefab(opts.prefab, math.Vector{float32(j*64) + opts.jOffset, float32(i*64) + opts.iOffset})
 				}
 			}
 		}
@@ -109,26 +132,19 @@ func createWalls(ctx *GameContext) {
 }
 
 func createFixtures(ctx *GameContext) {
-	build := func(i, j, w, h int) {
-		entity := ctx.EntityManager.Create()
-		ctx.GroupManager.AddGroup(entity, "physics")
-		ctx.GroupManager.AddGroup(entity, "bench")
-
-		body := physics.NewBody("bench", []physics.Fixture{
-			physics.NewBasicFixture(
-				0, 0, 32*float32(w), 32*float32(h), // bounds
-				0.0, 0.5, // material
-				0, 0, // friction
-			),
-		})
-		body.Position = math.Vector{float32(j*64) + 32, float32(i*64) + 64}
-		ctx.PhysicsComponentManager.AddComponent(entity, &physics.PhysicsComponent{Body: body})
+	prefabsByBit := map[maps.FixtureBit]string{
+		maps.FIXTURE_BENCH:       "bench",
+		maps.FIXTURE_CHAIR:       "chair",
+		maps.FIXTURE_GIANT_THING: "giant_thing",
 	}
 
 	for i := 0; i < ctx.TileMap.Height(); i++ {
 		for j := 0; j < ctx.TileMap.Width(); j++ {
 			if fixture, ok := ctx.TileMap.GetFixture(i, j); ok {
-				build(i, j, fixture.TileWidth, fixture.TileHeight)
+				if prefab, ok := prefabsByBit[fixture.Bit]; ok {
+					// Prefab bodies are centered on the tiles covered by the fixture
+					ctx.mustSpawnPrefab(prefab, math.Vector{float32(j*64 + fixture.TileWidth*32), float32(i*64 + fixture.TileHeight*32)})
+				}
 			}
 		}
 	}
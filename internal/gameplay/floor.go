@@ -0,0 +1,23 @@
+package gameplay
+
+import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
+	"github.com/efritz/lunar-fever/internal/gameplay/maps"
+)
+
+// floorMaterial returns the material of the ground at the given position: the floor of the
+// base, or the regolith outside of it.
+func (ctx *GameContext) floorMaterial(p math.Vector) *physics.Material {
+	gridSize := float32(ctx.TileMap.GridSize())
+	row := int(stdmath.Floor(float64(p.Y / gridSize)))
+	col := int(stdmath.Floor(float64(p.X / gridSize)))
+
+	if row >= 0 && row < ctx.TileMap.Height() && col >= 0 && col < ctx.TileMap.Width() && ctx.TileMap.GetBit(row, col, maps.FLOOR_BIT) {
+		return physics.MaterialSteel
+	}
+
+	return physics.MaterialRegolith
+}
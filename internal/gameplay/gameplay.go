@@ -7,6 +7,7 @@ import (
 	"github.com/efritz/lunar-fever/internal/common/math"
 	"github.com/efritz/lunar-fever/internal/engine"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/hierarchy"
 	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 	"github.com/efritz/lunar-fever/internal/engine/view"
@@ -39,43 +40,111 @@ func NewGameplay(engineCtx *engine.Context) view.View {
 
 		tileMap = maps.NewTileMap(100, 100, 64)
 	}
+
+	g, err := newGameplay(engineCtx, tileMap, func(gameCtx *GameContext) error {
+		createPlayer(gameCtx)
+		createScientist(gameCtx)
+		createRover(gameCtx)
+		createWalls(gameCtx)
+		createFixtures(gameCtx)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return g
+}
+
+// newGameplay wires up the systems for a world on the given tile map, then calls populate
+// to create its entities.
+func newGameplay(engineCtx *engine.Context, tileMap *maps.TileMap, populate func(gameCtx *GameContext) error) (*Gameplay, error) {
 	base := maps.ConstructBase(tileMap)
 
 	gameCtx := NewGameContext(engineCtx, tileMap, base)
 
-	updateSystemManager := system.NewManager()
-	updateSystemManager.Add(physics.NewPhysicsComponentSystem(gameCtx.EventManager, gameCtx.ComponentManager), 0)
-	updateSystemManager.Add(physics.NewCollisionResolution(gameCtx.EventManager, gameCtx.ComponentManager), 0)
-	updateSystemManager.Add(NewPlayerMovementSystem(gameCtx), 0)
-	updateSystemManager.Add(NewRoverMovementSystem(gameCtx), 0)
-	updateSystemManager.Add(NewCameraMovementSystem(gameCtx), 0)
-	updateSystemManager.Add(NewDoorOpenerSystem(gameCtx), 0)
-	updateSystemManager.Add(NewInteractionSystem(gameCtx), 0)
-	updateSystemManager.Add(NewHealthSystem(gameCtx), 0)
-	updateSystemManager.Add(NewNpcMovementSystem(gameCtx), 0)
-	updateSystemManager.Add(gameCtx.CameraDirector, 0)
+	updateSystemManager := system.NewManager(
+		system.WithSyncPoint(gameCtx.CommandBuffer),
+		system.WithFrameSyncPoint(gameCtx.EventManager),
+		system.WithParallelExecution(),
+	)
+	updateSystemManager.Add(physics.NewStepper(gameCtx.PhysicsWorld), 0,
+		system.Named("physics"),
+		// Collision events apply impact damage from rovers and their parts
+		system.Reads(roverPartComponentType),
+		system.Writes(physics.PhysicsComponentType{}, healthComponentType),
+	)
+	updateSystemManager.Add(NewPlayerMovementSystem(gameCtx), 0,
+		system.Named("player-movement"),
+		system.After("physics"),
+		system.Reads(healthComponentType),
+		system.Writes(physics.PhysicsComponentType{}),
+	)
+	updateSystemManager.Add(NewRoverMovementSystem(gameCtx), 0,
+		system.Named("rover-movement"),
+		system.After("player-movement"), // toggles control of the rover for the next frame
+		system.Reads(roverPartComponentType),
+		system.Writes(physics.PhysicsComponentType{}, hierarchy.HierarchyComponentType{}),
+	)
+	updateSystemManager.Add(NewCameraMovementSystem(gameCtx), 0,
+		system.Named("camera-movement"),
+	)
+	updateSystemManager.Add(NewDoorOpenerSystem(gameCtx), 0,
+		system.Named("door-opener"),
+		system.After("physics"),
+		system.Writes(physics.PhysicsComponentType{}),
+	)
+	updateSystemManager.Add(NewInteractionSystem(gameCtx), 0,
+		system.Named("interaction"),
+		system.After("player-movement"),
+		system.Reads(physics.PhysicsComponentType{}, healthComponentType),
+		system.Writes(interactionComponentType),
+	)
+	updateSystemManager.Add(NewHealthSystem(gameCtx), 0,
+		system.Named("health"),
+		system.Writes(healthComponentType),
+	)
+	updateSystemManager.Add(NewNpcMovementSystem(gameCtx), 0,
+		system.Named("npc-movement"),
+		system.After("physics"),
+		system.Writes(physics.PhysicsComponentType{}, pathfindingComponentType),
+	)
+	updateSystemManager.Add(hierarchy.NewHierarchySystem(gameCtx.HierarchyManager), 0,
+		system.Named("hierarchy"),
+		system.After("rover-movement", "npc-movement", "door-opener"),
+		system.Reads(physics.PhysicsComponentType{}),
+		system.Writes(hierarchy.HierarchyComponentType{}),
+	)
+	updateSystemManager.Add(gameCtx.CameraDirector, 0,
+		system.Named("camera-director"),
+		system.After("camera-movement"),
+	)
 
 	renderSystemManager := system.NewManager()
-	renderSystemManager.Add(NewRegolithRenderSystem(gameCtx), 0)
-	renderSystemManager.Add(maps.NewBaseRenderSystem(engineCtx, tileMap, base), 1)
-	renderSystemManager.Add(NewScientistRenderSystem(gameCtx), 2)
-	renderSystemManager.Add(NewRoverRenderSystem(gameCtx), 2)
-	renderSystemManager.Add(NewPhysicsRenderSystem(gameCtx), 3)
-	renderSystemManager.Add(NewDoorRenderSystem(gameCtx), 4)
-	renderSystemManager.Add(NewInteractionRenderSystem(gameCtx), 5)
-	renderSystemManager.Add(NewNpcMovementRenderSystem(gameCtx), 6)
-
-	createPlayer(gameCtx)
-	createScientist(gameCtx)
-	createRover(gameCtx)
-	createWalls(gameCtx)
-	createFixtures(gameCtx)
+	renderSystemManager.Add(NewRegolithRenderSystem(gameCtx), 0, system.Named("regolith-render"))
+	renderSystemManager.Add(maps.NewBaseRenderSystem(engineCtx, tileMap, base), 1, system.Named("base-render"))
+	renderSystemManager.Add(NewScientistRenderSystem(gameCtx), 2, system.Named("scientist-render"))
+	renderSystemManager.Add(NewRoverRenderSystem(gameCtx), 2, system.Named("rover-render"))
+	renderSystemManager.Add(NewPhysicsRenderSystem(gameCtx), 3, system.Named("physics-render"))
+	renderSystemManager.Add(NewDoorRenderSystem(gameCtx), 4, system.Named("door-render"))
+	renderSystemManager.Add(NewInteractionRenderSystem(gameCtx), 5, system.Named("interaction-render"))
+	renderSystemManager.Add(NewNpcMovementRenderSystem(gameCtx), 6, system.Named("npc-movement-render"))
+
+	if err := populate(gameCtx); err != nil {
+		return nil, err
+	}
+
+	// Rover parts are not saved, so they are rebuilt from the chassis here for new and
+	// loaded games alike
+	for _, rover := range gameCtx.RoverCollection.Entities() {
+		attachRoverParts(gameCtx, rover)
+	}
 
 	return &Gameplay{
 		GameContext:         gameCtx,
 		updateSystemManager: updateSystemManager,
 		renderSystemManager: renderSystemManager,
-	}
+	}, nil
 }
 
 func (g *Gameplay) Init() {
@@ -99,7 +168,7 @@ func (g *Gameplay) Update(elapsedMs int64, hasFocus bool) {
 
 	// Menu management
 	if g.Keyboard.IsKeyNewlyDown(glfw.KeyEscape) {
-		g.ViewManager.Add(menu.NewPauseMenu(g.Context))
+		g.ViewManager.Add(menu.NewPauseMenu(g.Context, g.Save, LoadGameplay))
 	}
 	if g.Keyboard.IsKeyNewlyDown(glfw.KeyTab) {
 		g.ViewManager.Add(menu.NewObjectiveMenu(g.Context))
@@ -107,14 +176,11 @@ func (g *Gameplay) Update(elapsedMs int64, hasFocus bool) {
 
 	// Center on player
 	if g.Keyboard.IsKeyNewlyDown(glfw.KeySpace) {
-		for _, entity := range g.PlayerCollection.Entities() {
-			component, ok := g.GameContext.PhysicsComponentManager.GetComponent(entity)
-			if !ok {
-				continue
+		if entity, ok := g.TagManager.Find("player"); ok {
+			if component, ok := g.GameContext.PhysicsComponentManager.GetComponent(entity); ok {
+				x1, y1, x2, y2 := component.Body.CoverBound()
+				g.GameContext.CameraDirector.LookAt(x1+(x2-x1)/2, y1+(y2-y1)/2, 1000)
 			}
-
-			x1, y1, x2, y2 := component.Body.CoverBound()
-			g.GameContext.CameraDirector.LookAt(x1+(x2-x1)/2, y1+(y2-y1)/2, 1000)
 		}
 	}
 
@@ -128,11 +194,30 @@ func (g *Gameplay) Update(elapsedMs int64, hasFocus bool) {
 		debug = !debug
 	}
 
+	// Dump physics for bug reports
+	if debug && g.Keyboard.IsKeyNewlyDown(glfw.KeyO) {
+		if err := g.dumpPhysics(debugPhysicsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to dump physics: %s\n", err)
+		}
+	}
+
 	g.updateSystemManager.Process(elapsedMs)
 }
 
 var debug = false
 
+const debugPhysicsFile = "physics.svg"
+
+func (g *Gameplay) dumpPhysics(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return g.PhysicsWorld.DebugDraw().WriteSVG(f)
+}
+
 func (g *Gameplay) Render(elapsedMs int64) {
 	g.renderMss = append(g.renderMss, elapsedMs)
 	g.renderMsTotal += elapsedMs
@@ -153,6 +238,17 @@ func (g *Gameplay) Render(elapsedMs int64) {
 			rendering.WithTextScale(0.5),
 			rendering.WithTextColor(rendering.Color{0, 0, 0, 1}),
 		)
+
+		timings := append(g.updateSystemManager.Timings(), g.renderSystemManager.Timings()...)
+		for i, timing := range timings {
+			font.Printf(
+				rendering.DisplayWidth-200,
+				float32(50+i*16),
+				fmt.Sprintf("%s: %.2fms", timing.Name, float64(timing.Average.Microseconds())/1000),
+				rendering.WithTextScale(0.35),
+				rendering.WithTextColor(rendering.Color{0, 0, 0, 1}),
+			)
+		}
 	}
 }
 
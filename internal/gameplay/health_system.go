@@ -1,41 +1,61 @@
 package gameplay
 
 import (
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/command"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 type healthSystem struct {
 	*GameContext
-	entityDamagedEventManager *EntityDamagedEventManager
-	entityDeathEventManager   *EntityDeathEventManager
+	entityDamagedEventManager    *EntityDamagedEventManager
+	entityDeathEventManager      *EntityDeathEventManager
+	collisionStartedEventManager *physics.CollisionStartedEventManager
+	listeners                    []event.ListenerHandle
 }
 
 func NewHealthSystem(ctx *GameContext) *healthSystem {
 	return &healthSystem{
-		GameContext:               ctx,
-		entityDamagedEventManager: NewEntityDamagedEventManager(ctx.EventManager),
-		entityDeathEventManager:   NewEntityDeathEventManager(ctx.EventManager),
+		GameContext:                  ctx,
+		entityDamagedEventManager:    NewEntityDamagedEventManager(ctx.EventManager),
+		entityDeathEventManager:      NewEntityDeathEventManager(ctx.EventManager),
+		collisionStartedEventManager: physics.NewCollisionStartedEventManager(ctx.EventManager),
 	}
 }
 
 func (s *healthSystem) Init() {
-	s.entityDamagedEventManager.AddListener(s)
+	s.listeners = append(s.listeners,
+		s.entityDamagedEventManager.AddListener(s),
+		s.entityDeathEventManager.AddListener(s),
+		s.collisionStartedEventManager.AddListener(s),
+	)
 }
 
-func (s *healthSystem) Exit() {}
+func (s *healthSystem) Exit() {
+	for _, handle := range s.listeners {
+		s.EventManager.RemoveListener(handle)
+	}
+	s.listeners = nil
+}
 
 func (s *healthSystem) Process(elapsedMs int64) {
 	// Temporary implementation
-	for _, entity := range s.PlayerCollection.Entities() {
-		healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
+	entity, ok := s.TagManager.Find("player")
+	if !ok {
+		return
+	}
 
-		if s.Keyboard.IsKeyNewlyDown(glfw.KeyB) {
-			healthComponent.Health -= 15
-			s.entityDamagedEventManager.Dispatch(EntityDamagedEvent{entity})
-		}
+	healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	if s.Keyboard.IsKeyNewlyDown(glfw.KeyB) {
+		healthComponent.Health -= 15
+		s.entityDamagedEventManager.Dispatch(EntityDamagedEvent{entity})
 	}
 }
 
@@ -46,6 +66,64 @@ func (s *healthSystem) OnEntityDamaged(e EntityDamagedEvent) {
 	}
 
 	if healthComponent.Health <= 0 {
-		s.entityDeathEventManager.Dispatch(EntityDeathEvent{e.Entity})
+		// Deaths are handled at the end of the frame, after every system has seen the damage
+		s.entityDeathEventManager.Enqueue(EntityDeathEvent{e.Entity})
+	}
+}
+
+// OnEntityDeath stops dead NPCs from walking. The changes are buffered as deaths may be
+// announced while other systems are iterating over the NPCs.
+func (s *healthSystem) OnEntityDeath(e EntityDeathEvent) {
+	if !s.GroupManager.HasGroup(e.Entity, "npc") {
+		return
 	}
+
+	s.CommandBuffer.RemoveGroup(e.Entity, "npc")
+	command.RemoveComponent(s.CommandBuffer, s.PathfindingComponentManager, e.Entity)
+}
+
+const (
+	impactMinSpeed    = 0.1 // px/ms the rover must be driving into its victim
+	impactDamageScale = 60  // damage per px/ms of velocity change of the victim
+)
+
+// OnCollisionStarted damages scientists run into by the rover in proportion to how hard
+// they were knocked away. Walking into a parked rover does no harm.
+func (s *healthSystem) OnCollisionStarted(e physics.CollisionStartedEvent) {
+	if rover, ok := s.roverOf(e.A); ok {
+		s.applyImpact(rover, e.B, e.Normal, e.Impulse)
+	} else if rover, ok := s.roverOf(e.B); ok {
+		s.applyImpact(rover, e.A, e.Normal.Neg(), e.Impulse)
+	}
+}
+
+// roverOf returns the rover the given entity is the chassis or a part of.
+func (s *healthSystem) roverOf(e entity.Entity) (entity.Entity, bool) {
+	if _, ok := s.RoverPartComponentManager.GetComponent(e); ok {
+		for parent, ok := s.HierarchyManager.Parent(e); ok; parent, ok = s.HierarchyManager.Parent(e) {
+			e = parent
+		}
+	}
+
+	return e, s.TagManager.HasTag(e, "rover")
+}
+
+func (s *healthSystem) applyImpact(rover, victim entity.Entity, normal math.Vector, impulse float32) {
+	roverComponent, ok := s.PhysicsComponentManager.GetComponent(rover)
+	if !ok || roverComponent.Body.LinearVelocity.Dot(normal) < impactMinSpeed {
+		return
+	}
+
+	victimComponent, ok := s.PhysicsComponentManager.GetComponent(victim)
+	if !ok || victimComponent.Body.Mass() == 0 {
+		return
+	}
+
+	healthComponent, ok := s.HealthComponentManager.GetComponent(victim)
+	if !ok || healthComponent.Health <= 0 {
+		return
+	}
+
+	healthComponent.Health -= impulse / victimComponent.Body.Mass() * impactDamageScale
+	s.entityDamagedEventManager.Dispatch(EntityDamagedEvent{victim})
 }
@@ -1,8 +1,12 @@
 package gameplay
 
+import "github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+
 type InteractionComponent struct {
 	Interacting   bool
 	CooldownTimer int64
+	Target        entity.Entity `json:"-"` // what the entity is facing, if HasTarget is set
+	HasTarget     bool          `json:"-"`
 }
 
 type InteractionComponentType struct{}
@@ -21,36 +21,40 @@ func (s *interactionRenderSystem) Init() {
 func (s *interactionRenderSystem) Exit() {}
 
 func (s *interactionRenderSystem) Process(elapsedMs int64) {
-	for _, entity := range s.PlayerCollection.Entities() {
-		physicsComponent, ok := s.PhysicsComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		interactionComponent, ok := s.InteractionComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		if !interactionComponent.Interacting && canInteract(physicsComponent, interactionComponent, healthComponent) {
-			s.SpriteBatch.Begin()
-
-			x1, y1, _, _ := physicsComponent.Body.NonorientedBound()
-			w := float32(15)
-			h := float32(15)
-
-			s.SpriteBatch.Draw(s.emptyTexture, x1, y1, w, h, rendering.WithOrigin(w/2, h/2))
-			s.SpriteBatch.End()
-
-			font.Printf(x1+4, y1+12, "e",
-				rendering.WithTextScale(0.3),
-				rendering.WithTextColor(rendering.Color{0, 0, 0, 0.5}),
-			)
-		}
+	entity, ok := s.TagManager.Find("player")
+	if !ok {
+		return
+	}
+
+	physicsComponent, ok := s.PhysicsComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	interactionComponent, ok := s.InteractionComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	if !interactionComponent.Interacting && canInteract(physicsComponent, interactionComponent, healthComponent) {
+		s.SpriteBatch.Begin()
+
+		body := s.PhysicsWorld.Interpolated(physicsComponent.Body)
+		x1, y1, _, _ := body.NonorientedBound()
+		w := float32(15)
+		h := float32(15)
+
+		s.SpriteBatch.Draw(s.emptyTexture, x1, y1, w, h, rendering.WithOrigin(w/2, h/2))
+		s.SpriteBatch.End()
+
+		font.Printf(x1+4, y1+12, "e",
+			rendering.WithTextScale(0.3),
+			rendering.WithTextColor(rendering.Color{0, 0, 0, 0.5}),
+		)
 	}
 }
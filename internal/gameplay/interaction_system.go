@@ -1,6 +1,8 @@
 package gameplay
 
 import (
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
 	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/go-gl/glfw/v3.2/glfw"
@@ -19,35 +21,56 @@ func (s *interactionSystem) Exit() {}
 
 var interactionCooldown = 0.5
 
+// interactionRange is how far past the edge of its body an entity can reach.
+const interactionRange = 32
+
 func (s *interactionSystem) Process(elapsedMs int64) {
-	for _, entity := range s.PlayerCollection.Entities() {
-		physicsComponent, ok := s.PhysicsComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		interactionComponent, ok := s.InteractionComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
-		if !ok {
-			return
-		}
-
-		interactionComponent.CooldownTimer -= elapsedMs
-
-		if s.Keyboard.IsKeyNewlyDown(glfw.KeyE) && canInteract(physicsComponent, interactionComponent, healthComponent) {
-			// TODO - should interact _against_ another object
-			interactionComponent.Interacting = true
-			interactionComponent.CooldownTimer = int64(interactionCooldown * 1000)
-		} else {
-			interactionComponent.Interacting = false
-		}
+	entity, ok := s.TagManager.Find("player")
+	if !ok {
+		return
+	}
+
+	physicsComponent, ok := s.PhysicsComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	interactionComponent, ok := s.InteractionComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	healthComponent, ok := s.HealthComponentManager.GetComponent(entity)
+	if !ok {
+		return
+	}
+
+	interactionComponent.CooldownTimer -= elapsedMs
+	interactionComponent.Target, interactionComponent.HasTarget = s.target(entity, physicsComponent.Body)
+
+	if s.Keyboard.IsKeyNewlyDown(glfw.KeyE) && canInteract(physicsComponent, interactionComponent, healthComponent) {
+		interactionComponent.Interacting = true
+		interactionComponent.CooldownTimer = int64(interactionCooldown * 1000)
+	} else {
+		interactionComponent.Interacting = false
 	}
 }
 
+// target returns the first entity in front of the given entity within reach. Walls block
+// the view but are not targets themselves.
+func (s *interactionSystem) target(e entity.Entity, body *physics.Body) (entity.Entity, bool) {
+	x1, _, x2, _ := body.NonorientedBound()
+	reach := (x2-x1)/2 + interactionRange
+	facing := body.Rotation.Mul(math.Vector{X: 0, Y: 1})
+
+	hit, ok := s.PhysicsWorld.Raycast(body.Position, body.Position.Add(facing.Muls(reach)), physics.Excluding(e))
+	if !ok || s.GroupManager.HasGroup(hit.Entity, "wall") {
+		return entity.Entity{}, false
+	}
+
+	return hit.Entity, true
+}
+
 func canInteract(physicsComponent *physics.PhysicsComponent, interactionComponent *InteractionComponent, healthComponent *HealthComponent) bool {
 	if healthComponent.Health <= 0 {
 		return false
@@ -61,5 +84,9 @@ func canInteract(physicsComponent *physics.PhysicsComponent, interactionComponen
 		return false
 	}
 
+	if !interactionComponent.HasTarget {
+		return false
+	}
+
 	return true
 }
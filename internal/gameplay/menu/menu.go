@@ -2,6 +2,7 @@ package menu
 
 import (
 	"github.com/efritz/lunar-fever/internal/engine"
+	"github.com/efritz/lunar-fever/internal/engine/rendering"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
@@ -19,6 +20,7 @@ type Menu struct {
 	initialized bool
 	selected    int
 	entries     []*MenuEntry
+	err         error // shown beneath the entries until the next selection
 }
 
 func NewMenu(engineCtx *engine.Context, delegate MenuDelegate) *Menu {
@@ -80,6 +82,7 @@ func (m *Menu) Update(elapsedMs int64, hasFocus bool) {
 	}
 
 	if m.Keyboard.IsKeyNewlyDown(glfw.KeyEnter) {
+		m.err = nil
 		m.entries[m.selected].OnSelect()
 	}
 
@@ -96,6 +99,21 @@ func (m *Menu) Render(elapsedMs int64) {
 	for i, entry := range m.entries {
 		entry.Render(elapsedMs, i, i == m.selected)
 	}
+
+	if m.err != nil {
+		font.Printf(
+			float32(128),
+			float32(128+32*len(m.entries)+16),
+			m.err.Error(),
+			rendering.WithTextScale(0.3),
+			rendering.WithTextColor(rendering.Color{1, 0.3, 0.3, 1}),
+		)
+	}
+}
+
+// ShowError displays the given error beneath the entries, e.g. when a selected entry fails.
+func (m *Menu) ShowError(err error) {
+	m.err = err
 }
 
 func (m *Menu) IsOverlay() bool {
@@ -10,7 +10,7 @@ import (
 	"github.com/efritz/lunar-fever/internal/gameplay/updates"
 )
 
-func NewMainMenu(engineCtx *engine.Context, gameplayFactory func(*engine.Context) view.View) view.View {
+func NewMainMenu(engineCtx *engine.Context, gameplayFactory func(*engine.Context) view.View, load func(*engine.Context) (view.View, error)) view.View {
 	updater, err := updates.NewUpdater()
 	if err != nil {
 		panic(err)
@@ -25,6 +25,7 @@ func NewMainMenu(engineCtx *engine.Context, gameplayFactory func(*engine.Context
 		menu.AddEntry("Download update", &downloadUpdateMenuEntry{updater: updater})
 	}
 	menu.AddEntry("Play", &gameplayMenuEntry{Context: engineCtx, gameplayFactory: gameplayFactory})
+	menu.AddEntry("Load game", &loadGameMenuEntry{Context: engineCtx, load: load, showError: menu.ShowError})
 	menu.AddEntry("Tile editor", &tileEditorMenuEntry{Context: engineCtx})
 	menu.AddEntry("Exit", &exitMenuEntry{exit: engineCtx.Game.Stop})
 
@@ -50,6 +51,22 @@ func (e *gameplayMenuEntry) OnSelect() {
 	Load(e.Context, e.gameplayFactory(e.Context), fakeLoader)
 }
 
+type loadGameMenuEntry struct {
+	*engine.Context
+	load      func(*engine.Context) (view.View, error)
+	showError func(err error)
+}
+
+func (e *loadGameMenuEntry) OnSelect() {
+	v, err := e.load(e.Context)
+	if err != nil {
+		e.showError(fmt.Errorf("failed to load game: %w", err))
+		return
+	}
+
+	Load(e.Context, v, fakeLoader)
+}
+
 type tileEditorMenuEntry struct {
 	*engine.Context
 }
@@ -1,6 +1,8 @@
 package menu
 
 import (
+	"fmt"
+
 	"github.com/efritz/lunar-fever/internal/engine"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 	"github.com/efritz/lunar-fever/internal/engine/view"
@@ -15,13 +17,15 @@ type PauseMenu struct {
 
 // setTransitionOnTime(250);
 
-func NewPauseMenu(engineCtx *engine.Context) view.View {
+func NewPauseMenu(engineCtx *engine.Context, save func() error, load func(*engine.Context) (view.View, error)) view.View {
 	delegate := &PauseMenu{Context: engineCtx}
 	menu := NewMenu(engineCtx, delegate)
 	v := view.NewTransitionView(menu, engineCtx.ViewManager)
 	delegate.beginExiting = v.BeginExiting
 
 	menu.AddEntry("Resume", &resumeMenuEntry{beginExiting: v.BeginExiting})
+	menu.AddEntry("Save game", &saveGameMenuEntry{save: save, beginExiting: v.BeginExiting, showError: menu.ShowError})
+	menu.AddEntry("Load game", &loadGameMenuEntry{Context: engineCtx, load: load, showError: menu.ShowError})
 	menu.AddEntry("Exit", &exitMenuEntry{exit: engineCtx.Game.Stop})
 
 	return v
@@ -74,3 +78,18 @@ type resumeMenuEntry struct {
 func (e *resumeMenuEntry) OnSelect() {
 	e.beginExiting()
 }
+
+type saveGameMenuEntry struct {
+	save         func() error
+	beginExiting func()
+	showError    func(err error)
+}
+
+func (e *saveGameMenuEntry) OnSelect() {
+	if err := e.save(); err != nil {
+		e.showError(fmt.Errorf("failed to save game: %w", err))
+		return
+	}
+
+	e.beginExiting()
+}
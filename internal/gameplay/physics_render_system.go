@@ -1,7 +1,11 @@
 package gameplay
 
 import (
+	stdmath "math"
+
+	"github.com/efritz/lunar-fever/internal/common/math"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 )
 
@@ -10,6 +14,19 @@ type physicsRenderSystem struct {
 	emptyTexture rendering.Texture
 }
 
+const (
+	debugLineWidth    = 1
+	debugCircleSides  = 16
+	debugNormalLength = 8
+	debugContactSize  = 3
+)
+
+var debugBodyColors = map[physics.BodyType]rendering.Color{
+	physics.BodyDynamic:   {.2, .5, 1, .8},
+	physics.BodyStatic:    {.5, .5, .5, .8},
+	physics.BodyKinematic: {.2, .75, .4, .8},
+}
+
 func NewPhysicsRenderSystem(ctx *GameContext) system.System {
 	return &physicsRenderSystem{GameContext: ctx}
 }
@@ -25,26 +42,81 @@ func (s *physicsRenderSystem) Process(elapsedMs int64) {
 		return
 	}
 
-	s.SpriteBatch.Begin()
+	frame := s.PhysicsWorld.DebugDraw()
 
-	for _, entity := range s.PhysicsCollection.Entities() {
-		component, ok := s.PhysicsComponentManager.GetComponent(entity)
-		if !ok || component.CollisionsDisabled {
-			continue
-		}
+	s.SpriteBatch.Begin()
 
-		x01, y01, x02, y02 := component.Body.CoverBound()
-		w := x02 - x01
-		h := y02 - y01
+	for _, b := range frame.Bounds {
+		color := rendering.Color{1, 0, 1, .35}
+		s.drawLine(math.Vector{b.X1, b.Y1}, math.Vector{b.X2, b.Y1}, color)
+		s.drawLine(math.Vector{b.X2, b.Y1}, math.Vector{b.X2, b.Y2}, color)
+		s.drawLine(math.Vector{b.X2, b.Y2}, math.Vector{b.X1, b.Y2}, color)
+		s.drawLine(math.Vector{b.X1, b.Y2}, math.Vector{b.X1, b.Y1}, color)
+	}
 
-		s.SpriteBatch.Draw(s.emptyTexture, x01, y01, w, h, rendering.WithColor(rendering.Color{1, 0, 1, .35}))
+	for _, shape := range frame.Shapes {
+		s.drawShape(shape)
+	}
 
-		x1, y1, x2, y2 := component.Body.NonorientedBound()
-		w = x2 - x1
-		h = y2 - y1
-		s.SpriteBatch.Draw(s.emptyTexture, x1, y1, w, h, rendering.WithColor(rendering.Color{1, 1, 0, .35}), rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w/2, h/2))
+	for _, joint := range frame.Joints {
+		s.drawLine(joint.AnchorA, joint.AnchorB, rendering.Color{1, .6, 0, 1})
+	}
 
+	for _, contact := range frame.Contacts {
+		color := rendering.Color{1, 0, 0, 1}
+		s.SpriteBatch.Draw(s.emptyTexture, contact.Point.X-debugContactSize/2, contact.Point.Y-debugContactSize/2, debugContactSize, debugContactSize, rendering.WithColor(color))
+		s.drawLine(contact.Point, contact.Point.Add(contact.Normal.Muls(debugNormalLength)), color)
 	}
 
 	s.SpriteBatch.End()
 }
+
+func (s *physicsRenderSystem) drawShape(shape physics.DebugShape) {
+	color := debugBodyColors[shape.Type]
+	if shape.Sleeping {
+		color.A /= 3
+	}
+
+	switch shape.Shape {
+	case physics.ShapeCircle:
+		s.drawCircle(shape.Vertices[0], shape.Radius, color)
+
+	case physics.ShapeCapsule:
+		a, b := shape.Vertices[0], shape.Vertices[1]
+		offset := b.Sub(a).Normalize().Orthogonalize().Muls(shape.Radius)
+		s.drawLine(a.Add(offset), b.Add(offset), color)
+		s.drawLine(a.Sub(offset), b.Sub(offset), color)
+		s.drawCircle(a, shape.Radius, color)
+		s.drawCircle(b, shape.Radius, color)
+
+	default:
+		for i, v := range shape.Vertices {
+			s.drawLine(v, shape.Vertices[(i+1)%len(shape.Vertices)], color)
+		}
+	}
+}
+
+func (s *physicsRenderSystem) drawCircle(center math.Vector, radius float32, color rendering.Color) {
+	point := func(i int) math.Vector {
+		angle := 2 * stdmath.Pi * float32(i) / debugCircleSides
+		return center.Add(math.Vector{math.Cos32(angle), math.Sin32(angle)}.Muls(radius))
+	}
+
+	for i := 0; i < debugCircleSides; i++ {
+		s.drawLine(point(i), point(i+1), color)
+	}
+}
+
+// drawLine draws a thin quad from a to b, rotated about a.
+func (s *physicsRenderSystem) drawLine(a, b math.Vector, color rendering.Color) {
+	d := b.Sub(a)
+
+	s.SpriteBatch.Draw(
+		s.emptyTexture,
+		a.X, a.Y-debugLineWidth/2,
+		d.Len(), debugLineWidth,
+		rendering.WithColor(color),
+		rendering.WithRotation(math.Atan232(d.Y, d.X)),
+		rendering.WithOrigin(0, debugLineWidth/2),
+	)
+}
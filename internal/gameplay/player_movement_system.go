@@ -5,22 +5,31 @@ import (
 
 	"github.com/efritz/lunar-fever/internal/common/math"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/event"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
 type playerMovementSystem struct {
 	*GameContext
+	entityDeathEventManager *EntityDeathEventManager
+	entityDeathListener     event.ListenerHandle
 }
 
 func NewPlayerMovementSystem(ctx *GameContext) system.System {
-	return &playerMovementSystem{GameContext: ctx}
+	return &playerMovementSystem{
+		GameContext:             ctx,
+		entityDeathEventManager: NewEntityDeathEventManager(ctx.EventManager),
+	}
 }
 
 func (s *playerMovementSystem) Init() {
-	NewEntityDeathEventManager(s.GameContext.EventManager).AddListener(s)
+	s.entityDeathListener = s.entityDeathEventManager.AddListener(s)
 }
 
-func (s *playerMovementSystem) Exit() {}
+func (s *playerMovementSystem) Exit() {
+	s.entityDeathEventManager.RemoveListener(s.entityDeathListener)
+}
 
 func (g *playerMovementSystem) Process(elapsedMs int64) {
 	if controllingRover {
@@ -50,43 +59,52 @@ func (g *playerMovementSystem) Process(elapsedMs int64) {
 	mx := g.Camera.Unprojectx(float32(g.Mouse.X()))
 	my := g.Camera.UnprojectY(float32(g.Mouse.Y()))
 
-	for _, entity := range g.PlayerCollection.Entities() {
-		physicsComponent, ok := g.PhysicsComponentManager.GetComponent(entity)
-		if !ok {
-			continue
-		}
-
-		if healthComponent, ok := g.HealthComponentManager.GetComponent(entity); !ok || healthComponent.Health <= 0 {
-			physicsComponent.Body.LinearVelocity = math.Vector{0, 0}
-			physicsComponent.Body.AngularVelocity = 0
-			continue
-		}
-
-		angle := math.Atan232(my-physicsComponent.Body.Position.Y, mx-physicsComponent.Body.Position.X)
-		if angle < 0 {
-			angle = (2 * stdmath.Pi) - (-angle)
-		}
-		angle -= float32(stdmath.Pi / 2)
-
-		if physicsComponent.Body.Orient != angle {
-			physicsComponent.Body.SetOrient(angle)
-		}
-
-		mod := float32(1000)
-		if playerXDir != 0 || playerYDir != 0 {
-			speed := float32(.35)
-			transitionSpeed := float32(4)
-
-			physicsComponent.Body.LinearVelocity = physicsComponent.Body.LinearVelocity.
-				Muls(1 - (float32(elapsedMs) / mod * transitionSpeed)).
-				Add(math.Vector{playerXDir, playerYDir}.Muls(speed * float32(elapsedMs) / mod * transitionSpeed))
-		} else {
-			transitionSpeed := float32(8)
-
-			physicsComponent.Body.LinearVelocity = physicsComponent.Body.LinearVelocity.Muls(1 - (float32(elapsedMs) / mod * transitionSpeed))
-			physicsComponent.Body.AngularVelocity = physicsComponent.Body.AngularVelocity * (1 - (float32(elapsedMs) / mod * transitionSpeed))
-		}
+	entity, ok := g.TagManager.Find("player")
+	if !ok {
+		return
+	}
+
+	physicsComponent, ok := g.PhysicsComponentManager.GetComponent(entity)
+	if !ok {
+		return
 	}
+
+	body := physicsComponent.Body
+	if healthComponent, ok := g.HealthComponentManager.GetComponent(entity); !ok || healthComponent.Health <= 0 {
+		brake(body, playerBraking)
+		return
+	}
+
+	angle := math.Atan232(my-body.Position.Y, mx-body.Position.X)
+	if angle < 0 {
+		angle = (2 * stdmath.Pi) - (-angle)
+	}
+	angle -= float32(stdmath.Pi / 2)
+
+	if body.Orient != angle {
+		body.SetOrient(angle)
+	}
+
+	if playerXDir != 0 || playerYDir != 0 {
+		// Close a fraction of the difference to the target velocity every second
+		target := math.Vector{playerXDir, playerYDir}.Muls(playerSpeed)
+		body.ApplyForce(target.Sub(body.LinearVelocity).Muls(body.Mass() * playerAcceleration / 1000))
+	} else {
+		brake(body, playerBraking)
+	}
+}
+
+const (
+	playerSpeed        = float32(.35) // px/ms
+	playerAcceleration = float32(4)   // fraction of the difference to the target speed closed per second
+	playerBraking      = float32(8)   // fraction of speed lost per second without input
+)
+
+// brake pushes against the motion of the body so that it loses the given fraction of its
+// speed per second.
+func brake(body *physics.Body, rate float32) {
+	body.ApplyForce(body.LinearVelocity.Muls(-body.Mass() * rate / 1000))
+	body.ApplyTorque(-body.AngularVelocity * body.Inertia() * rate / 1000)
 }
 
 func (s *playerMovementSystem) OnEntityDeath(e EntityDeathEvent) {
@@ -0,0 +1,61 @@
+package gameplay
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/efritz/lunar-fever/assets"
+	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/snapshot"
+)
+
+// SpawnPrefab creates an entity from the named prefab in assets/prefabs. Prefabs use the
+// same layout as entities in a save file: a list of tags, a list of groups, and components
+// keyed by their registered serializer name. If the prefab has a physics body, it is moved
+// to the given position.
+func (ctx *GameContext) SpawnPrefab(name string, position math.Vector) (entity.Entity, error) {
+	prefab, err := ctx.loadPrefab(name)
+	if err != nil {
+		return entity.Entity{}, err
+	}
+
+	e, err := ctx.Serializer.RestoreEntity(prefab)
+	if err != nil {
+		return entity.Entity{}, fmt.Errorf("spawning prefab %q: %w", name, err)
+	}
+
+	if physicsComponent, ok := ctx.PhysicsComponentManager.GetComponent(e); ok {
+		physicsComponent.Body.Position = position
+	}
+
+	return e, nil
+}
+
+func (ctx *GameContext) loadPrefab(name string) (snapshot.EntitySnapshot, error) {
+	if prefab, ok := ctx.prefabs[name]; ok {
+		return prefab, nil
+	}
+
+	data, err := assets.LoadPrefab(name)
+	if err != nil {
+		return snapshot.EntitySnapshot{}, fmt.Errorf("loading prefab %q: %w", name, err)
+	}
+
+	var prefab snapshot.EntitySnapshot
+	if err := json.Unmarshal(data, &prefab); err != nil {
+		return snapshot.EntitySnapshot{}, fmt.Errorf("parsing prefab %q: %w", name, err)
+	}
+
+	ctx.prefabs[name] = prefab
+	return prefab, nil
+}
+
+func (ctx *GameContext) mustSpawnPrefab(name string, position math.Vector) entity.Entity {
+	e, err := ctx.SpawnPrefab(name, position)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
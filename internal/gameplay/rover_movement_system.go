@@ -4,7 +4,9 @@ import (
 	stdmath "math"
 
 	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/go-gl/glfw/v3.2/glfw"
 )
 
@@ -51,42 +53,134 @@ func (g *roverMovementSystem) Process(elapsedMs int64) {
 			continue
 		}
 
-		// angle := math.Atan232(my-component.Body.Position.Y, mx-component.Body.Position.X)
-		// if angle < 0 {
-		// 	angle = (2 * stdmath.Pi) - (-angle)
-		// }
-		//
-		// 	component.Body.SetOrient(angle)
-
-		mod := float32(1000)
 		if roverXDir != 0 {
 			dx := stdmath.Pi * float32(roverXDir) / (128 + 64)
-			tireRotation, _ = math.Clamp(tireRotation+dx*2, -stdmath.Pi/6, stdmath.Pi/6)
-
-			if roverYDir != 0 {
-				component.Body.SetOrient(component.Body.Orient + dx*-float32(roverYDir))
-			}
+			g.steer(entity, func(angle float32) float32 {
+				angle, _ = math.Clamp(angle+dx*2, -roverMaxSteer, roverMaxSteer)
+				return angle
+			})
 		} else {
-			if tireRotation > 0 {
-				tireRotation -= stdmath.Pi / 128
-			} else {
-				tireRotation += stdmath.Pi / 128
+			g.steer(entity, func(angle float32) float32 {
+				if angle > 0 {
+					return angle - stdmath.Pi/128
+				}
+
+				return angle + stdmath.Pi/128
+			})
+		}
+
+		g.drive(entity, component.Body, roverYDir, float32(elapsedMs))
+	}
+}
+
+const (
+	roverTopSpeed     = float32(.35) // px/ms
+	roverAcceleration = roverTopSpeed / 250
+	roverBraking      = float32(8) // fraction of speed lost per second without throttle
+	roverSteerTimeMs  = 50         // time the steering motors take to reach the steering angle
+	roverGripMs       = 16         // time the tires take to stop sliding sideways with unit friction
+)
+
+// drive turns the front tires towards their steering angle and pushes the rover along the
+// heading of each tire. Tires resist sliding sideways as well as the floor beneath them
+// allows, so steered tires turn the chassis. Without throttle the rover brakes.
+func (g *roverMovementSystem) drive(rover entity.Entity, chassis *physics.Body, roverYDir int64, elapsedMs float32) {
+	tires := g.tires(rover)
+	if len(tires) == 0 {
+		return
+	}
+
+	// Each tire carries an equal share of the whole rover
+	mass := chassis.Mass()
+	for _, tire := range tires {
+		mass += tire.body.Mass()
+	}
+	share := mass / float32(len(tires))
+
+	if chassis.Mass() == 0 || chassis.Inertia() == 0 {
+		return
+	}
+
+	for _, tire := range tires {
+		if joint := tire.roverPartComponent.Steering; joint != nil {
+			delta := tire.target - joint.Angle()
+			joint.MotorSpeed = delta / roverSteerTimeMs
+			if math.Abs32(delta) > stdmath.Pi/128 {
+				tire.body.Wake()
 			}
 		}
 
 		if roverYDir != 0 {
-			speed := float32(.35)
-			transitionSpeed := float32(4)
+			heading := tire.body.Rotation.Mul(math.Vector{0, float32(roverYDir)})
+			if chassis.LinearVelocity.Dot(heading) < roverTopSpeed {
+				tire.body.ApplyForce(heading.Muls(share * roverAcceleration))
+			}
+		}
 
-			component.Body.LinearVelocity = component.Body.LinearVelocity.
-				Muls(1 - (float32(elapsedMs) / mod * transitionSpeed)).
-				Add(component.Body.Rotation.Mul(math.Vector{0, float32(roverYDir)}).
-					Muls(speed * float32(elapsedMs) / mod * transitionSpeed))
-		} else {
-			transitionSpeed := float32(8)
+		// Grip is applied to the chassis where the tire is mounted, one tire at a time, so
+		// that each tire sees the sliding left over by the others
+		r := tire.body.Position.Sub(chassis.Position)
+		lateral := tire.body.Rotation.Mul(math.Vector{1, 0})
+		slide := chassis.LinearVelocity.Add(r.Crosss(chassis.AngularVelocity)).Dot(lateral)
+		rn := r.Cross(lateral)
+		_, friction := physics.Friction(tire.body.Fixtures[0].Material, g.floorMaterial(tire.body.Position))
+		grip, _ := math.Clamp(elapsedMs/roverGripMs*friction, 0, 1)
+		chassis.ApplyLinearImpulse(lateral.Muls(-slide*grip/(1/chassis.Mass()+rn*rn/chassis.Inertia())), tire.body.Position)
+	}
+
+	if roverYDir == 0 {
+		brake(chassis, roverBraking)
+		for _, tire := range tires {
+			brake(tire.body, roverBraking)
+		}
+	}
+}
+
+type roverTire struct {
+	body               *physics.Body
+	roverPartComponent *RoverPartComponent
+	target             float32 // steering angle
+}
+
+func (g *roverMovementSystem) tires(rover entity.Entity) []roverTire {
+	var tires []roverTire
+	for _, axle := range g.HierarchyManager.Children(rover) {
+		for _, tire := range g.HierarchyManager.Children(axle) {
+			physicsComponent, ok := g.PhysicsComponentManager.GetComponent(tire)
+			if !ok {
+				continue
+			}
+
+			roverPartComponent, ok := g.RoverPartComponentManager.GetComponent(tire)
+			if !ok {
+				continue
+			}
 
-			component.Body.LinearVelocity = component.Body.LinearVelocity.Muls(1 - (float32(elapsedMs) / mod * transitionSpeed))
-			component.Body.AngularVelocity = component.Body.AngularVelocity * (1 - (float32(elapsedMs) / mod * transitionSpeed))
+			hierarchyComponent, ok := g.HierarchyManager.GetComponent(tire)
+			if !ok {
+				continue
+			}
+
+			tires = append(tires, roverTire{physicsComponent.Body, roverPartComponent, hierarchyComponent.Local.Rotation})
+		}
+	}
+
+	return tires
+}
+
+// steer updates the steering angle of the steerable tires of the given rover, which is
+// kept as their local rotation.
+func (g *roverMovementSystem) steer(rover entity.Entity, f func(angle float32) float32) {
+	for _, axle := range g.HierarchyManager.Children(rover) {
+		for _, tire := range g.HierarchyManager.Children(axle) {
+			roverPartComponent, ok := g.RoverPartComponentManager.GetComponent(tire)
+			if !ok || roverPartComponent.Steering == nil {
+				continue
+			}
+
+			if hierarchyComponent, ok := g.HierarchyManager.GetComponent(tire); ok {
+				hierarchyComponent.Local.Rotation = f(hierarchyComponent.Local.Rotation)
+			}
 		}
 	}
 }
@@ -0,0 +1,27 @@
+package gameplay
+
+import "github.com/efritz/lunar-fever/internal/engine/physics"
+
+type RoverPart int
+
+const (
+	RoverAxle RoverPart = iota
+	RoverTireLeft
+	RoverTireRight
+)
+
+// RoverPartComponent marks an entity attached to a rover chassis. Axles are positioned by
+// the hierarchy manager. Tires have bodies of their own jointed to the chassis; the local
+// rotation of a steerable tire is the angle its steering joint is driven towards.
+type RoverPartComponent struct {
+	Part     RoverPart
+	Steering *physics.RevoluteJoint // nil for tires welded in place
+}
+
+type RoverPartComponentType struct{}
+
+var roverPartComponentType = RoverPartComponentType{}
+
+func (c *RoverPartComponent) ComponentType() RoverPartComponentType {
+	return roverPartComponentType
+}
@@ -1,8 +1,9 @@
 package gameplay
 
 import (
-	"github.com/efritz/lunar-fever/internal/common/math"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/hierarchy"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 )
 
@@ -48,42 +49,85 @@ func (s *roverRenderSystem) Process(elapsedMs int64) {
 			continue
 		}
 
-		x01, y01, x02, y02 := component.Body.CoverBound()
+		body := s.PhysicsWorld.Interpolated(component.Body)
+
+		x01, y01, x02, y02 := body.CoverBound()
 		w := x02 - x01
 		h := y02 - y01
 
 		// s.SpriteBatch.Draw(s.emptyTexture, x01, y01, w, h, rendering.WithColor(rendering.Color{1, 0, 1, .35}))
 
-		x1, y1, x2, y2 := component.Body.NonorientedBound()
+		x1, y1, x2, y2 := body.NonorientedBound()
 		w = x2 - x1
 		h = y2 - y1
-		// s.SpriteBatch.Draw(s.emptyTexture, x1, y1, w, h, rendering.WithColor(rendering.Color{1, 1, 0, .35}), rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w/2, h/2))
+		// s.SpriteBatch.Draw(s.emptyTexture, x1, y1, w, h, rendering.WithColor(rendering.Color{1, 1, 0, .35}), rendering.WithRotation(body.Orient), rendering.WithOrigin(w/2, h/2))
 
-		w2 := float32(177)
-		h2 := float32(45)
-		axleOffset := float32(60)
-		w3 := float32(22)
-		h3 := h2
+		// Parts are placed from the interpolated chassis rather than their propagated world
+		// transforms so that they move with it between physics steps
+		root := hierarchy.Transform{Position: body.Position, Rotation: body.Orient}
+		axles := s.HierarchyManager.Children(entity)
 
-		v2 := component.Body.Rotation.Mul(math.Vector{-w2 / 2, -axleOffset}).Add(math.Vector{x1 + w/2 - w3, y1 + h/2 - h3/2})
-		v3 := component.Body.Rotation.Mul(math.Vector{+w2 / 2, -axleOffset}).Add(math.Vector{x1 + w/2, y1 + h/2 - h3/2})
+		// Axles beneath the chassis
+		for _, axle := range axles {
+			s.drawPart(axle, root)
+		}
+		s.SpriteBatch.Draw(s.baseTexture, x1, y1, w, h, rendering.WithRotation(body.Orient), rendering.WithOrigin(w/2, h/2))
 
-		// Axel and body
-		s.SpriteBatch.Draw(s.axleTexture, (x1+w/2)-w2/2, (y1+h/2)-h2/2+axleOffset, w2, h2, rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w2/2, h2/2-axleOffset))
-		s.SpriteBatch.Draw(s.axleTexture, (x1+w/2)-w2/2, (y1+h/2)-h2/2-axleOffset, w2, h2, rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w2/2, h2/2+axleOffset))
-		s.SpriteBatch.Draw(s.baseTexture, x1, y1, w, h, rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w/2, h/2))
+		// Tires
+		for _, axle := range axles {
+			for _, tire := range s.HierarchyManager.Children(axle) {
+				s.drawTire(tire)
+			}
+		}
+	}
 
-		// Front tires
-		s.SpriteBatch.Draw(s.tireTexture, v2.X, v2.Y, w3, h3, rendering.WithRotation(component.Body.Orient+tireRotation), rendering.WithOrigin(w3, h3/2))
-		s.SpriteBatch.Draw(s.tireTexture, v3.X, v3.Y, w3, h3, rendering.WithRotation(component.Body.Orient+tireRotation), rendering.WithOrigin(0, h3/2), rendering.WithSpriteEffects(rendering.SpriteEffectFlipHorizontal))
+	s.SpriteBatch.End()
+}
 
-		// Back tires
-		s.SpriteBatch.Draw(s.tireTexture, (x1+w/2)-w2/2-w3, (y1+h/2)-h3/2+axleOffset, w3, h3, rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(w2/2+w3, h3/2-axleOffset))
-		s.SpriteBatch.Draw(s.tireTexture, (x1+w/2)+w2/2, (y1+h/2)-h3/2+axleOffset, w3, h3, rendering.WithRotation(component.Body.Orient), rendering.WithOrigin(-w2/2, h3/2-axleOffset), rendering.WithSpriteEffects(rendering.SpriteEffectFlipHorizontal))
+// drawPart draws an axle centered on its position relative to the world transform of its
+// parent.
+func (s *roverRenderSystem) drawPart(e entity.Entity, parent hierarchy.Transform) {
+	roverPartComponent, ok := s.RoverPartComponentManager.GetComponent(e)
+	if !ok || roverPartComponent.Part != RoverAxle {
+		return
 	}
 
-	s.SpriteBatch.End()
+	world := s.partTransform(e, parent)
+	p := world.Position
+
+	w, h := float32(roverAxleWidth), float32(roverAxleHeight)
+	s.SpriteBatch.Draw(s.axleTexture, p.X-w/2, p.Y-h/2, w, h, rendering.WithRotation(world.Rotation), rendering.WithOrigin(w/2, h/2))
+}
+
+// drawTire draws a tire centered on its body.
+func (s *roverRenderSystem) drawTire(e entity.Entity) {
+	roverPartComponent, ok := s.RoverPartComponentManager.GetComponent(e)
+	if !ok {
+		return
+	}
+
+	physicsComponent, ok := s.PhysicsComponentManager.GetComponent(e)
+	if !ok {
+		return
+	}
+
+	body := s.PhysicsWorld.Interpolated(physicsComponent.Body)
+	p := body.Position
+
+	opts := []rendering.DrawOptionFunc{rendering.WithRotation(body.Orient), rendering.WithOrigin(roverTireWidth/2, roverTireHeight/2)}
+	if roverPartComponent.Part == RoverTireRight {
+		opts = append(opts, rendering.WithSpriteEffects(rendering.SpriteEffectFlipHorizontal))
+	}
+
+	w, h := float32(roverTireWidth), float32(roverTireHeight)
+	s.SpriteBatch.Draw(s.tireTexture, p.X-w/2, p.Y-h/2, w, h, opts...)
 }
 
-// TODO - deglobalize
-var tireRotation float32
+func (s *roverRenderSystem) partTransform(e entity.Entity, parent hierarchy.Transform) hierarchy.Transform {
+	hierarchyComponent, ok := s.HierarchyManager.GetComponent(e)
+	if !ok {
+		return parent
+	}
+
+	return parent.Apply(hierarchyComponent.Local)
+}
@@ -0,0 +1,97 @@
+package gameplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/efritz/lunar-fever/internal/engine"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
+	"github.com/efritz/lunar-fever/internal/engine/ecs/snapshot"
+	"github.com/efritz/lunar-fever/internal/engine/view"
+	"github.com/efritz/lunar-fever/internal/gameplay/maps"
+)
+
+// Joints are not part of a save. The only jointed entities are the parts of the rover,
+// which are left out and rebuilt with their joints from the chassis by newGameplay (see
+// attachRoverParts). Jointing anything else means serializing joints and bumping the version.
+const (
+	savePath        = "save.dat"
+	saveFileVersion = 1
+)
+
+type saveFile struct {
+	Version int
+	TileMap []byte
+	World   snapshot.Snapshot
+}
+
+func newSerializer(ctx *GameContext) *snapshot.Serializer {
+	// Rover parts are rebuilt from their chassis when a game is loaded
+	isSaved := func(e entity.Entity) bool {
+		_, ok := ctx.RoverPartComponentManager.GetComponent(e)
+		return !ok
+	}
+
+	serializer := snapshot.NewSerializer(ctx.EntityManager, ctx.TagManager, ctx.GroupManager, snapshot.WithFilter(isSaved))
+	snapshot.Register(serializer, "physics", ctx.PhysicsComponentManager)
+	snapshot.Register(serializer, "pathfinding", ctx.PathfindingComponentManager)
+	snapshot.Register(serializer, "health", ctx.HealthComponentManager)
+	snapshot.Register(serializer, "interaction", ctx.InteractionComponentManager)
+	return serializer
+}
+
+// Save writes the tile map and the full entity state of the running game to disk.
+func (g *Gameplay) Save() error {
+	var tileMap bytes.Buffer
+	if err := maps.WriteTileMap(g.TileMap, &tileMap); err != nil {
+		return err
+	}
+
+	world, err := g.Serializer.Capture()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(saveFile{
+		Version: saveFileVersion,
+		TileMap: tileMap.Bytes(),
+		World:   world,
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(savePath, data, 0644)
+}
+
+// LoadGameplay constructs a new game from the most recent save.
+func LoadGameplay(engineCtx *engine.Context) (view.View, error) {
+	data, err := os.ReadFile(savePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var save saveFile
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+	if save.Version != saveFileVersion {
+		return nil, fmt.Errorf("unsupported save file version %d (expected %d)", save.Version, saveFileVersion)
+	}
+
+	tileMap, err := maps.ReadTileMap(bytes.NewReader(save.TileMap))
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := newGameplay(engineCtx, tileMap, func(gameCtx *GameContext) error {
+		return gameCtx.Serializer.Restore(save.World)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/efritz/lunar-fever/internal/common/math"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/entity"
 	"github.com/efritz/lunar-fever/internal/engine/ecs/system"
+	"github.com/efritz/lunar-fever/internal/engine/event"
 	"github.com/efritz/lunar-fever/internal/engine/physics"
 	"github.com/efritz/lunar-fever/internal/engine/rendering"
 )
@@ -17,7 +18,8 @@ type scientistRenderSystem struct {
 	interactAtlases []rendering.Texture
 	idleAtlas       []rendering.Texture
 	deathAtlas      []rendering.Texture
-	renderDetails   map[int64]*renderDetails
+	renderDetails   map[entity.Entity]*renderDetails
+	removedObserver event.ListenerHandle
 }
 
 type renderDetails struct {
@@ -33,7 +35,7 @@ type renderDetails struct {
 func NewScientistRenderSystem(ctx *GameContext) system.System {
 	return &scientistRenderSystem{
 		GameContext:   ctx,
-		renderDetails: map[int64]*renderDetails{},
+		renderDetails: map[entity.Entity]*renderDetails{},
 	}
 }
 
@@ -85,9 +87,16 @@ func (s *scientistRenderSystem) Init() {
 		s.TextureLoader.Load("character/scientist_1/die_1/sci_fall_1_5").Region(0, 0, 64, 128),
 		s.TextureLoader.Load("character/scientist_1/die_1/sci_fall_1_6").Region(0, 0, 64, 128),
 	}
+
+	// Forget animation state of scientists that no longer have a body to draw
+	s.removedObserver = s.PhysicsComponentManager.OnRemoved(func(e entity.Entity, _ *physics.PhysicsComponent) {
+		delete(s.renderDetails, e)
+	})
 }
 
-func (s *scientistRenderSystem) Exit() {}
+func (s *scientistRenderSystem) Exit() {
+	s.PhysicsComponentManager.RemoveObserver(s.removedObserver)
+}
 
 const (
 	minStartAnimationSpeed    = 0.05 // Minimum speed to start animation
@@ -115,13 +124,13 @@ func (s *scientistRenderSystem) render(elapsedMs int64, entity entity.Entity) {
 		return
 	}
 
-	details, ok := s.renderDetails[entity.ID]
+	details, ok := s.renderDetails[entity]
 	if !ok {
 		details = &renderDetails{
 			lastAnimationFrame: s.walkAtlases[2],
 			animationQueue:     &animationQueue{},
 		}
-		s.renderDetails[entity.ID] = details
+		s.renderDetails[entity] = details
 	}
 
 	interacting := false
@@ -149,11 +158,7 @@ func (s *scientistRenderSystem) render(elapsedMs int64, entity entity.Entity) {
 		if physicsComponent.Body.Name == "scientist" {
 			old := physicsComponent.Body
 			physicsComponent.Body = physics.NewBody("scientist-dead", []physics.Fixture{
-				physics.NewBasicFixture(
-					0, 0, 48/2, 48, // bounds
-					0.3, 0.2, // material
-					0, 0, // friction
-				),
+				physics.NewBasicFixture(0, 0, 48/2, 48, physics.MaterialScientist),
 			})
 			physicsComponent.Body.Position = old.Position.Add(rotate(math.Vector{0, -(48 / 2)}, old.Orient))
 			physicsComponent.Body.Orient = old.Orient
@@ -172,23 +177,25 @@ func (s *scientistRenderSystem) render(elapsedMs int64, entity entity.Entity) {
 			details.lastAnimationFrame = frame
 		}
 
-		x1, y1, x2, y2 := physicsComponent.Body.NonorientedBound()
+		body := s.PhysicsWorld.Interpolated(physicsComponent.Body)
+		x1, y1, x2, y2 := body.NonorientedBound()
 		w := x2 - x1
 		h := y2 - y1
 
-		s.SpriteBatch.Draw(details.lastAnimationFrame, x1, y1, w, h, rendering.WithRotation(physicsComponent.Body.Orient), rendering.WithOrigin(w/2, h/2))
+		s.SpriteBatch.Draw(details.lastAnimationFrame, x1, y1, w, h, rendering.WithRotation(body.Orient), rendering.WithOrigin(w/2, h/2))
 	} else {
 		const spriteSize = 48.0
 		w := float32(spriteSize)
 		h := float32(spriteSize)
-		x1 := physicsComponent.Body.Position.X - w/2
-		y1 := physicsComponent.Body.Position.Y - h/2
+		body := s.PhysicsWorld.Interpolated(physicsComponent.Body)
+		x1 := body.Position.X - w/2
+		y1 := body.Position.Y - h/2
 
 		// Draw body
-		s.SpriteBatch.Draw(s.selectBodyTexture(physicsComponent, interacting, details, elapsedMs), x1, y1, w, h, rendering.WithRotation(physicsComponent.Body.Orient), rendering.WithOrigin(w/2, h/2))
+		s.SpriteBatch.Draw(s.selectBodyTexture(physicsComponent, interacting, details, elapsedMs), x1, y1, w, h, rendering.WithRotation(body.Orient), rendering.WithOrigin(w/2, h/2))
 
 		// Always draw head
-		s.SpriteBatch.Draw(s.headAtlas, x1, y1, w, h, rendering.WithRotation(physicsComponent.Body.Orient), rendering.WithOrigin(w/2, h/2))
+		s.SpriteBatch.Draw(s.headAtlas, x1, y1, w, h, rendering.WithRotation(body.Orient), rendering.WithOrigin(w/2, h/2))
 	}
 }
 